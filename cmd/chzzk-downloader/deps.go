@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/setup"
+)
+
+// deps 명령: 의존성 상태 확인, 업데이트, 복구
+func runDeps(args []string) int {
+	if len(args) == 0 {
+		printDepsUsage()
+		return 2
+	}
+
+	switch args[0] {
+	case "status":
+		return runDepsStatus(args[1:])
+	case "update":
+		return runDepsUpdate(args[1:])
+	case "repair":
+		return runDepsRepair(args[1:])
+	case "bundle":
+		return runDepsBundle(args[1:])
+	default:
+		printDepsUsage()
+		return 2
+	}
+}
+
+func printDepsUsage() {
+	fmt.Println("사용법: chzzk-downloader deps <명령> [옵션] [이름...]")
+	fmt.Println()
+	fmt.Println("  status  설치된 의존성과 버전 확인")
+	fmt.Println("  update  설치한 의존성을 목록에 고정된 버전으로 업데이트 (실패하면 이전 버전으로 되돌림)")
+	fmt.Println("  repair  망가지거나 없는 의존성 다시 설치")
+	fmt.Println("  bundle  인터넷이 없는 곳에서 설치할 오프라인 번들 만들기")
+	fmt.Println()
+	fmt.Println("update, repair에 -bundle <폴더|.zip|.tar.xz>를 주면 번들의 파일로 설치합니다.")
+	fmt.Printf("\n설치 폴더: %s\n", config.GetDependentDir())
+	fmt.Printf("미러/목록 덮어쓰기: %s (없으면 %s)\n",
+		filepath.Join(config.GetBaseDir(), config.DependencyManifestFile),
+		filepath.Join(config.GetExecutableDir(), config.DependencyManifestFile))
+}
+
+// selectDependencies 이름으로 의존성 선택 (이름이 없으면 전체)
+func selectDependencies(names []string) ([]setup.Dependency, error) {
+	deps, err := setup.Dependencies()
+	if err != nil {
+		return nil, err
+	}
+	if deps == nil {
+		return nil, fmt.Errorf("이 플랫폼(%s)은 의존성 관리를 지원하지 않습니다. ffmpeg를 설치해 PATH에 추가하세요", setup.Platform())
+	}
+	if len(names) == 0 {
+		return deps, nil
+	}
+
+	var selected []setup.Dependency
+	for _, name := range names {
+		dep, ok, err := setup.FindDependency(name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("알 수 없는 의존성: %s", name)
+		}
+		selected = append(selected, dep)
+	}
+	return selected, nil
+}
+
+// useBundle -bundle이 지정되었으면 오프라인 번들을 사용하도록 설정 (반환한 함수로 정리)
+func useBundle(path string) (func(), error) {
+	if path == "" {
+		return func() {}, nil
+	}
+	cleanup, err := setup.UseBundle(path)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("오프라인 번들 사용: %s\n\n", path)
+	return cleanup, nil
+}
+
+func runDepsStatus(args []string) int {
+	fs := flag.NewFlagSet("deps status", flag.ExitOnError)
+	fs.Parse(args)
+
+	deps, err := selectDependencies(fs.Args())
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	healthy := true
+	for _, dep := range deps {
+		status := dep.Status()
+		fmt.Printf("%s\n", dep.Name)
+		switch status.Source {
+		case setup.SourceMissing:
+			fmt.Println("  상태: 없음")
+			healthy = false
+		case setup.SourcePath:
+			fmt.Printf("  위치: %s (PATH)\n", status.Path)
+		default:
+			fmt.Printf("  위치: %s\n", status.Path)
+		}
+		if status.Reported != "" {
+			fmt.Printf("  실행 버전: %s\n", status.Reported)
+		}
+		if status.Record != nil {
+			fmt.Printf("  설치 버전: %s (%s)\n", status.Record.Version, status.Record.InstalledAt.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("  고정 버전: %s\n", dep.Version)
+		if status.UpdateAvailable() {
+			fmt.Println("  업데이트 가능: deps update로 설치하세요")
+		}
+		if status.Problem != nil {
+			fmt.Printf("  문제: %v\n", status.Problem)
+			healthy = false
+		}
+	}
+
+	if !healthy {
+		fmt.Println("\n문제가 있는 의존성은 deps repair로 다시 설치할 수 있습니다.")
+		return 1
+	}
+	return 0
+}
+
+func runDepsUpdate(args []string) int {
+	fs := flag.NewFlagSet("deps update", flag.ExitOnError)
+	check := fs.Bool("check", false, "설치하지 않고 업데이트할 의존성만 표시")
+	force := fs.Bool("force", false, "같은 버전이어도 다시 설치")
+	bundle := fs.String("bundle", "", "인터넷 대신 이 오프라인 번들(폴더, .zip, .tar.xz)에서 설치")
+	fs.Parse(args)
+
+	cleanup, err := useBundle(*bundle)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	defer cleanup()
+
+	deps, err := selectDependencies(fs.Args())
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	if *check {
+		for _, dep := range deps {
+			status := dep.Status()
+			switch {
+			case status.Source == setup.SourcePath:
+				fmt.Printf("%s: PATH의 파일 사용 중 (%s)\n", dep.Name, status.Reported)
+			case status.Source == setup.SourceMissing:
+				fmt.Printf("%s: 설치되지 않음 (고정 버전 %s)\n", dep.Name, dep.Version)
+			case status.UpdateAvailable():
+				installed := "알 수 없음"
+				if status.Record != nil {
+					installed = status.Record.Version
+				}
+				fmt.Printf("%s: %s -> %s\n", dep.Name, installed, dep.Version)
+			default:
+				fmt.Printf("%s: 최신 (%s)\n", dep.Name, dep.Version)
+			}
+		}
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := 0
+	for _, dep := range deps {
+		updated, err := setup.UpdateDependency(ctx, dep, *force)
+		if err != nil {
+			fmt.Printf("%s 업데이트 실패: %v\n", dep.Name, err)
+			if ctx.Err() != nil {
+				return 1
+			}
+			failed++
+			continue
+		}
+		if updated {
+			fmt.Printf("%s %s 설치 완료\n", dep.Name, dep.Version)
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runDepsRepair(args []string) int {
+	fs := flag.NewFlagSet("deps repair", flag.ExitOnError)
+	bundle := fs.String("bundle", "", "인터넷 대신 이 오프라인 번들(폴더, .zip, .tar.xz)에서 설치")
+	fs.Parse(args)
+
+	cleanup, err := useBundle(*bundle)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	defer cleanup()
+
+	deps, err := selectDependencies(fs.Args())
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := 0
+	for _, dep := range deps {
+		repaired, err := setup.RepairDependency(ctx, dep)
+		if err != nil {
+			fmt.Printf("%s 복구 실패: %v\n", dep.Name, err)
+			if ctx.Err() != nil {
+				return 1
+			}
+			failed++
+			continue
+		}
+		if repaired {
+			fmt.Printf("%s %s 다시 설치 완료\n", dep.Name, dep.Version)
+		} else {
+			fmt.Printf("%s 정상\n", dep.Name)
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runDepsBundle(args []string) int {
+	fs := flag.NewFlagSet("deps bundle", flag.ExitOnError)
+	platformList := fs.String("platform", setup.Platform(), "번들에 넣을 플랫폼 (쉼표로 구분, all이면 전체: "+strings.Join(setup.Platforms(), ", ")+")")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "사용법: chzzk-downloader deps bundle [-platform linux/amd64,windows/amd64] <폴더>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	platforms := setup.Platforms()
+	if *platformList != "all" {
+		platforms = strings.Split(*platformList, ",")
+		for i := range platforms {
+			platforms[i] = strings.TrimSpace(platforms[i])
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := setup.CreateBundle(ctx, fs.Arg(0), platforms); err != nil {
+		fmt.Printf("번들 만들기 실패: %v\n", err)
+		return 1
+	}
+	fmt.Printf("오프라인 번들을 만들었습니다: %s\n", fs.Arg(0))
+	fmt.Println("인터넷이 없는 곳에서 deps repair -bundle <폴더>로 설치하세요.")
+	return 0
+}
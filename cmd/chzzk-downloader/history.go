@@ -0,0 +1,196 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"chzzk-downloader/internal/downloader"
+)
+
+// history 명령: 다운로드 기록 조회, 검색, 내보내기, 정리
+func runHistory(args []string) int {
+	if len(args) == 0 {
+		printHistoryUsage()
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return runHistoryList(args[1:])
+	case "search":
+		return runHistorySearch(args[1:])
+	case "export":
+		return runHistoryExport(args[1:])
+	case "prune":
+		return runHistoryPrune(args[1:])
+	default:
+		printHistoryUsage()
+		return 2
+	}
+}
+
+func printHistoryUsage() {
+	fmt.Println("사용법: chzzk-downloader history <명령> [옵션]")
+	fmt.Println()
+	fmt.Println("  list    받은 영상 목록 (최근 순)")
+	fmt.Println("  search  제목, 채널명, 영상 번호로 검색")
+	fmt.Println("  export  CSV 또는 JSON으로 내보내기")
+	fmt.Println("  prune   파일이 없어졌거나 오래된 기록 삭제")
+	fmt.Printf("\n기록 파일: %s\n", downloader.ArchivePath())
+}
+
+func runHistoryList(args []string) int {
+	fs := flag.NewFlagSet("history list", flag.ExitOnError)
+	limit := fs.Int("n", 20, "표시할 개수 (0이면 전체)")
+	fs.Parse(args)
+
+	archive, err := downloader.LoadArchive()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	records := archive.Sorted()
+	if *limit > 0 && len(records) > *limit {
+		records = records[:*limit]
+	}
+	printHistoryRecords(records)
+	if len(records) < len(archive.Records) {
+		fmt.Printf("\n(전체 %d개 중 %d개 표시, -n 0으로 전체 보기)\n", len(archive.Records), len(records))
+	}
+	return 0
+}
+
+func runHistorySearch(args []string) int {
+	fs := flag.NewFlagSet("history search", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "사용법: chzzk-downloader history search <검색어>")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	archive, err := downloader.LoadArchive()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	printHistoryRecords(archive.Search(fs.Arg(0)))
+	return 0
+}
+
+func runHistoryExport(args []string) int {
+	fs := flag.NewFlagSet("history export", flag.ExitOnError)
+	format := fs.String("format", "csv", "내보낼 형식 (csv, json)")
+	output := fs.String("o", "", "저장할 파일 (비어 있으면 화면에 출력)")
+	fs.Parse(args)
+
+	archive, err := downloader.LoadArchive()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	var export func(io.Writer) error
+	switch *format {
+	case "csv":
+		export = archive.ExportCSV
+	case "json":
+		export = archive.ExportJSON
+	default:
+		fmt.Printf("지원하지 않는 형식입니다: %s (csv, json)\n", *format)
+		return 2
+	}
+
+	if *output == "" {
+		err = export(os.Stdout)
+	} else {
+		err = exportToFile(*output, export)
+	}
+	if err != nil {
+		fmt.Printf("내보내기 실패: %v\n", err)
+		return 1
+	}
+
+	if *output != "" {
+		fmt.Printf("%d개 기록을 %s에 저장했습니다.\n", len(archive.Records), *output)
+	}
+	return 0
+}
+
+// exportToFile 파일을 만들어 기록을 내보내는 함수
+// 닫을 때 남은 내용이 기록되므로 Close 오류도 반환
+func exportToFile(path string, export func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runHistoryPrune(args []string) int {
+	fs := flag.NewFlagSet("history prune", flag.ExitOnError)
+	missing := fs.Bool("missing", true, "파일이 없어진 기록 삭제")
+	olderThan := fs.Duration("older-than", 0, "이 기간보다 오래된 기록 삭제 (예: 720h)")
+	dryRun := fs.Bool("dry-run", false, "삭제할 기록만 표시하고 실제로 삭제하지 않음")
+	fs.Parse(args)
+
+	archive, err := downloader.LoadArchive()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	removed := archive.Prune(downloader.PruneOptions{Missing: *missing, OlderThan: *olderThan})
+	if len(removed) == 0 {
+		fmt.Println("삭제할 기록이 없습니다.")
+		return 0
+	}
+
+	printHistoryRecords(removed)
+	if *dryRun {
+		fmt.Printf("\n%d개 기록이 삭제 대상입니다 (-dry-run).\n", len(removed))
+		return 0
+	}
+
+	if err := downloader.SaveArchive(archive); err != nil {
+		fmt.Printf("기록 저장 실패: %v\n", err)
+		return 1
+	}
+	fmt.Printf("\n%d개 기록을 삭제했습니다.\n", len(removed))
+	return 0
+}
+
+// printHistoryRecords 기록 목록을 표 형식으로 출력
+func printHistoryRecords(records []downloader.ArchiveRecord) {
+	if len(records) == 0 {
+		fmt.Println("기록이 없습니다.")
+		return
+	}
+
+	for _, record := range records {
+		status := ""
+		if !record.Exists() {
+			status = " (파일 없음)"
+		}
+		fmt.Printf("%s  %-9d %-6s %10s  [%s] %s%s\n",
+			record.DownloadedAt.Local().Format("2006-01-02 15:04"),
+			record.VideoNo,
+			record.Quality,
+			fmt.Sprintf("%.1f MB", float64(record.Size)/(1024*1024)),
+			record.Channel,
+			record.Title,
+			status,
+		)
+		fmt.Printf("    %s\n", record.Path)
+	}
+}
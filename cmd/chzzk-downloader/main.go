@@ -2,22 +2,31 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"chzzk-downloader/internal/api"
 	"chzzk-downloader/internal/config"
 	"chzzk-downloader/internal/downloader"
 	"chzzk-downloader/internal/setup"
+	"chzzk-downloader/internal/update"
 	"chzzk-downloader/internal/utils"
 )
 
 const VERSION = "0.2.1"
 
+// 완료 정보에 표시할 빠진 구간 최대 개수 (나머지는 보고서 파일 참고)
+const maxSummaryGaps = 5
+
 // 로컬 의존성 확인 함수(setup 패키지의 함수를 사용)
 func checkDependencies() bool {
 	return setup.CheckDependencies()
@@ -42,12 +51,18 @@ func ensureDependencies() bool {
 	}
 
 	fmt.Println("\n==== 의존성 설치 시작 ====")
-	err := setup.InstallDependencies()
+	// 설치 중 Ctrl+C를 누르면 받던 파일을 남기고 중단 (다음에 이어받음)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := setup.InstallDependencies(ctx)
+	stop()
 	if err != nil {
 		fmt.Printf("의존성 설치 중 오류 발생: %v\n", err)
+		fmt.Println("chzzk-downloader deps repair로 다시 시도할 수 있습니다.")
+		fmt.Println("인터넷이 없으면 deps repair -bundle <오프라인 번들>로 설치하세요.")
 		return false
 	}
-	fmt.Println("==== 의존성 설치 완료 ====\n")
+	fmt.Println("==== 의존성 설치 완료 ====")
+	fmt.Println()
 	return true
 }
 
@@ -151,7 +166,341 @@ func displayRecentVods(vods []config.RecentVodInfo) {
 	fmt.Println("(번호를 입력하여 선택하거나 새 URL을 입력하세요)")
 }
 
+// 출력 형식 선택 함수
+func selectContainer(scanner *bufio.Scanner, defaultName string) downloader.Container {
+	defaultContainer, err := downloader.ParseContainer(defaultName)
+	if err != nil {
+		fmt.Printf("%v\n기본 형식(%s)을 사용합니다.\n", err, downloader.DefaultContainer)
+		defaultContainer = downloader.DefaultContainer
+	}
+
+	fmt.Println("\n[ 출력 형식 ]")
+	fmt.Println("--------------------")
+	defaultIndex := 0
+	for idx, c := range downloader.Containers {
+		if c == defaultContainer {
+			defaultIndex = idx
+		}
+		fmt.Printf("%d. %s\n", idx+1, c.Description())
+	}
+	fmt.Println("--------------------")
+
+	for {
+		fmt.Printf("출력 형식 번호를 선택하세요 (Enter = %d번): ", defaultIndex+1)
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		selected := defaultContainer
+		if choice != "" {
+			choiceInt, err := strconv.Atoi(choice)
+			if err != nil || choiceInt < 1 || choiceInt > len(downloader.Containers) {
+				fmt.Println("잘못된 선택입니다. 1~" + strconv.Itoa(len(downloader.Containers)) + " 사이의 번호를 입력해주세요.")
+				continue
+			}
+			selected = downloader.Containers[choiceInt-1]
+		}
+
+		// 선택한 형식을 다음 기본값으로 저장
+		config.UpdateUserSettings(func(s *config.UserSettings) {
+			s.Container = string(selected)
+		})
+
+		fmt.Printf("선택된 출력 형식: %s\n", selected.Description())
+		return selected
+	}
+}
+
+// 트랜스코딩 프로필 선택 함수 (nil이면 변환하지 않음)
+func selectTranscodeProfile(scanner *bufio.Scanner) *downloader.TranscodeProfile {
+	profiles, err := downloader.LoadTranscodeProfiles()
+	if err != nil {
+		fmt.Printf("트랜스코딩 프로필을 불러오지 못했습니다: %v\n", err)
+		return nil
+	}
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	fmt.Println("\n[ 트랜스코딩 프로필 ]")
+	fmt.Println("--------------------")
+	fmt.Println("0. 사용 안 함 (원본 그대로 저장)")
+	for idx, p := range profiles {
+		fmt.Printf("%d. %s - %s\n", idx+1, p.Name, p.Description)
+	}
+	fmt.Println("--------------------")
+
+	for {
+		fmt.Print("프로필 번호를 선택하세요 (Enter = 0번): ")
+		scanner.Scan()
+		choice := strings.TrimSpace(scanner.Text())
+
+		if choice == "" || choice == "0" {
+			return nil
+		}
+
+		choiceInt, err := strconv.Atoi(choice)
+		if err != nil || choiceInt < 1 || choiceInt > len(profiles) {
+			fmt.Println("잘못된 선택입니다. 0~" + strconv.Itoa(len(profiles)) + " 사이의 번호를 입력해주세요.")
+			continue
+		}
+
+		selected := profiles[choiceInt-1]
+		if err := downloader.CheckTranscodeProfile(&selected); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("선택된 프로필: %s\n", selected.Name)
+		return &selected
+	}
+}
+
+// 파트 분할 선택 함수 (nil이면 분할하지 않음)
+func selectSplit(scanner *bufio.Scanner, template string) *downloader.SplitOptions {
+	for {
+		fmt.Print("\n파트 분할 (예: 1h, 2GiB / Enter = 분할 안 함): ")
+		scanner.Scan()
+		split, err := downloader.ParseSplitOptions(scanner.Text())
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if split != nil {
+			split.NameTemplate = template
+			fmt.Printf("%s 파트로 나눠 저장합니다.\n", split.String())
+		}
+		return split
+	}
+}
+
+// 챕터(타임스탬프 목록) 파일 선택 함수
+func selectChapters(scanner *bufio.Scanner) []downloader.Chapter {
+	for {
+		fmt.Print("\n타임스탬프 목록 파일 (예: 01:23:45 보스전 / Enter = 사용 안 함): ")
+		scanner.Scan()
+		path := strings.Trim(strings.TrimSpace(scanner.Text()), `"`)
+		if path == "" {
+			return nil
+		}
+
+		chapters, err := downloader.LoadChapterFile(path)
+		if err != nil {
+			fmt.Printf("챕터 파일을 읽지 못했습니다: %v\n", err)
+			continue
+		}
+
+		fmt.Printf("챕터 %d개를 불러왔습니다.\n", len(chapters))
+		return chapters
+	}
+}
+
+// -min-free 값 해석 (0이면 감시하지 않음)
+func parseMinFree(value string) (int64, error) {
+	size, err := utils.ParseByteSize(value)
+	if err != nil {
+		return 0, fmt.Errorf("최소 여유 공간을 해석할 수 없습니다: %s (예: 1GiB, 500MB, 0)", value)
+	}
+	return size, nil
+}
+
+// consolePrompter 터미널에서 중복 파일 처리 방법을 묻는 DuplicatePrompter
+type consolePrompter struct {
+	scanner *bufio.Scanner
+}
+
+// AskDuplicate 출력 파일이 이미 있을 때 처리 방법을 물어봄
+func (p *consolePrompter) AskDuplicate(outputFile string) downloader.DuplicatePolicy {
+	choices := downloader.DuplicatePolicies[1:] // ask 제외
+
+	fmt.Printf("파일 '%s'이(가) 이미 존재합니다.\n", outputFile)
+	for {
+		fmt.Println("어떻게 하시겠습니까?")
+		for idx, policy := range choices {
+			fmt.Printf("%d. %s\n", idx+1, policy.Description())
+		}
+		fmt.Printf("번호를 선택하세요 (1-%d): ", len(choices))
+
+		p.scanner.Scan()
+		choiceInt, err := strconv.Atoi(strings.TrimSpace(p.scanner.Text()))
+		if err != nil || choiceInt < 1 || choiceInt > len(choices) {
+			fmt.Println("잘못된 입력입니다. 1~" + strconv.Itoa(len(choices)) + " 사이의 번호를 입력해주세요.")
+			continue
+		}
+		return choices[choiceInt-1]
+	}
+}
+
+// extractPortableFlag 하위 명령과 관계없이 어디에 있든 -portable 옵션을 꺼냄
+// -portable, --portable, -portable=<true|false> 형식을 모두 받으며 하위 명령의 옵션 목록에는 넘기지 않음
+func extractPortableFlag(args []string) ([]string, bool, error) {
+	var rest []string
+	portable := false
+	for i, arg := range args {
+		if arg == "--" {
+			return append(rest, args[i:]...), portable, nil
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "-portable" && name != "--portable" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			portable = true
+			continue
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("-portable 옵션 값이 잘못되었습니다: %s", value)
+		}
+		portable = b
+	}
+	return rest, portable, nil
+}
+
+// migrateLegacyFiles 실행 파일 옆에 있던 이전 버전의 설정과 데이터를 사용자 폴더로 옮김
+// -progress json의 표준 출력을 섞지 않도록 안내는 표준 오류로 출력 (옵션을 읽기 전에 실행됨)
+func migrateLegacyFiles() {
+	migrations, err := config.MigrateLegacyFiles()
+	for _, m := range migrations {
+		fmt.Fprintf(os.Stderr, "이전 위치의 파일을 옮겼습니다: %s -> %s\n", m.From, m.To)
+		if m.Err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", m.Err)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "이전 설정을 옮기지 못했습니다: %v\n", err)
+		fmt.Fprintf(os.Stderr, "실행 파일 옆의 파일을 그대로 쓰려면 -portable 옵션으로 실행하세요.\n")
+	}
+	if len(migrations) > 0 || err != nil {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// newProgressReporter -progress 방식의 진행 표시 생성
+// json이면 표준 출력에는 JSON Lines만 나가도록 안내, 입력 요청, 요약 등 나머지 출력은 모두 표준 오류로 보냄
+func newProgressReporter(mode string) (downloader.ProgressReporter, error) {
+	reporter, err := downloader.NewProgressReporter(mode, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "json" {
+		os.Stdout = os.Stderr
+	}
+	return reporter, nil
+}
+
 func main() {
+	// 지난 업데이트에서 남은 실행 파일 정리 (Windows는 실행 중인 파일을 바로 지울 수 없음)
+	if exePath, err := update.ExecutablePath(); err == nil {
+		update.CleanupOld(exePath)
+	}
+
+	// 포터블 모드 확인 후 이전 위치의 설정 옮기기
+	args, portable, err := extractPortableFlag(os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	os.Args = append(os.Args[:1], args...)
+	config.SetPortable(portable)
+	migrateLegacyFiles()
+
+	// 하위 명령 처리
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "merge":
+			os.Exit(runMerge(os.Args[2:]))
+		case "history":
+			os.Exit(runHistory(os.Args[2:]))
+		case "deps":
+			os.Exit(runDeps(os.Args[2:]))
+		case "update":
+			os.Exit(runUpdate(os.Args[2:]))
+		}
+	}
+
+	progressMode := flag.String("progress", "line", "진행 표시 방식 (line: 한 줄, multi: 작업별 막대, json: JSON Lines)")
+	containerFlag := flag.String("container", "", "이번 실행의 기본 출력 형식 (mp4, fmp4, mkv, ts)")
+	profileFlag := flag.String("profile", "", "트랜스코딩 프로필 이름 (profiles.json에 정의, 지정하면 선택 과정 생략)")
+	splitFlag := flag.String("split", "", "길이(예: 1h) 또는 크기(예: 2GiB)마다 파트로 분할 (지정하면 선택 과정 생략)")
+	chaptersFlag := flag.String("chapters", "", "타임스탬프 목록 파일 (텍스트 또는 .json, 지정하면 선택 과정 생략)")
+	duplicateFlag := flag.String("on-duplicate", "ask", "출력 파일이 이미 있을 때 (ask, overwrite, skip, resume, rename, keep-larger)")
+	minFreeFlag := flag.String("min-free", "1GiB", "받는 중 여유 공간이 이보다 줄어들면 일시 정지 (0이면 사용 안 함)")
+	stallTimeoutFlag := flag.Duration("stall-timeout", 2*time.Minute, "이 시간 동안 진행이 없으면 마지막 저장 위치부터 다시 받음 (0이면 사용 안 함)")
+	maxRestartsFlag := flag.Int("max-restarts", 3, "진행이 멈췄을 때 다시 받는 최대 횟수")
+	backendFlag := flag.String("backend", "", "다운로드 백엔드 (auto, streamlink, hls, dash, ffmpeg, 비어 있으면 settings.json의 backend)")
+	workersFlag := flag.Int("segment-workers", downloader.DefaultSegmentWorkers, fmt.Sprintf("HLS 세그먼트를 동시에 받는 수 (1~%d)", downloader.MaxSegmentWorkers))
+	skipLostFlag := flag.Bool("skip-lost-segments", false, "끝내 받지 못한 세그먼트를 건너뛰고 계속 받음 (빠진 구간은 <파일명>.gaps.json에 기록)")
+	noArchiveFlag := flag.Bool("no-archive", false, "다운로드 기록을 확인하지 않고 받으며 기록도 남기지 않음")
+	splitTemplateFlag := flag.String("split-template", downloader.DefaultSplitTemplate, "분할 파일명 템플릿 ({name}: 파일명, {part}: 파트 번호)")
+	// 실제 처리는 extractPortableFlag에서 하며 도움말에 표시하려고 등록
+	flag.Bool("portable", false, "설정과 데이터를 사용자 폴더 대신 실행 파일 옆에 저장 (모든 하위 명령에 적용)")
+	flag.Parse()
+
+	// 진행 상황 출력기 준비
+	reporter, err := newProgressReporter(*progressMode)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// -on-duplicate 옵션 확인
+	duplicatePolicy, err := downloader.ParseDuplicatePolicy(*duplicateFlag)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// -min-free 옵션 확인
+	minFreeSpace, err := parseMinFree(*minFreeFlag)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// -backend 옵션 확인
+	if *backendFlag != "" {
+		if _, err := downloader.ParseBackend(*backendFlag); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	// -segment-workers 옵션 확인
+	if *workersFlag < 1 || *workersFlag > downloader.MaxSegmentWorkers {
+		fmt.Printf("-segment-workers는 1~%d 사이여야 합니다\n", downloader.MaxSegmentWorkers)
+		os.Exit(2)
+	}
+
+	// -profile 옵션 확인
+	var flagProfile *downloader.TranscodeProfile
+	if *profileFlag != "" {
+		flagProfile, err = downloader.FindTranscodeProfile(*profileFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	// -split 옵션 확인
+	flagSplit, err := downloader.ParseSplitOptions(*splitFlag)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if flagSplit != nil {
+		flagSplit.NameTemplate = *splitTemplateFlag
+	}
+
+	// -chapters 옵션 확인
+	var flagChapters []downloader.Chapter
+	if *chaptersFlag != "" {
+		flagChapters, err = downloader.LoadChapterFile(*chaptersFlag)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
 	fmt.Printf("==== 치지직 다운로더 (v%s) ====\n\n", VERSION)
 
 	// 의존성 확인 및 설치
@@ -162,6 +511,25 @@ func main() {
 		return
 	}
 
+	// ffmpeg 기능 확인 (결과는 ffmpeg 파일이 바뀔 때까지 캐시)
+	if caps, err := downloader.ProbeFFmpeg(); err != nil {
+		fmt.Printf("ffmpeg 기능을 확인하지 못했습니다: %v\n\n", err)
+	} else {
+		fmt.Printf("ffmpeg %s\n\n", caps.Version)
+		if err := caps.CheckBasics(); err != nil {
+			fmt.Println(err)
+			fmt.Print("\n종료하려면 Enter 키를 누르세요...")
+			bufio.NewReader(os.Stdin).ReadBytes('\n')
+			return
+		}
+	}
+	if flagProfile != nil {
+		if err := downloader.CheckTranscodeProfile(flagProfile); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 
 	// 사용자 설정 불러오기
@@ -170,8 +538,18 @@ func main() {
 		fmt.Printf("설정을 불러오는 중 오류 발생: %v\n", err)
 		// 오류 발생 시 기본 설정 사용
 		userSettings = config.UserSettings{
-			DownloadFolder: filepath.Join(config.GetBaseDir(), "downloads"),
+			DownloadFolder: config.GetDefaultDownloadDir(),
+		}
+	}
+
+	// 이전 실행에서 끝나지 않은 다운로드 안내
+	if staleParts := downloader.FindStalePartFiles(userSettings.DownloadFolder); len(staleParts) > 0 {
+		fmt.Println("[ 끝나지 않은 다운로드 ]")
+		for _, part := range staleParts {
+			fmt.Printf("- %s\n", strings.TrimSuffix(filepath.Base(part), downloader.PartSuffix))
 		}
+		fmt.Println("(같은 영상을 같은 화질로 다시 받으면 이어서 받습니다)")
+		fmt.Println()
 	}
 
 	for {
@@ -367,7 +745,7 @@ func main() {
 		// 저장된 다운로드 폴더 또는 기본 다운로드 폴더 설정
 		defaultFolder := userSettings.DownloadFolder
 		if defaultFolder == "" {
-			defaultFolder = filepath.Join(config.GetBaseDir(), "downloads")
+			defaultFolder = config.GetDefaultDownloadDir()
 		}
 
 		var outputFolder string
@@ -402,11 +780,76 @@ func main() {
 			break
 		}
 
+		// 출력 형식 선택 (-container 옵션이 있으면 설정 대신 기본값으로 사용)
+		defaultContainer := userSettings.Container
+		if *containerFlag != "" {
+			defaultContainer = *containerFlag
+		}
+		container := selectContainer(scanner, defaultContainer)
+		userSettings.Container = string(container)
+
+		// 트랜스코딩 프로필 선택
+		profile := flagProfile
+		if profile == nil {
+			profile = selectTranscodeProfile(scanner)
+		}
+
+		// 파트 분할 선택
+		split := flagSplit
+		if split == nil {
+			split = selectSplit(scanner, *splitTemplateFlag)
+		}
+
+		// 챕터 파일 선택 (TS는 챕터를 지원하지 않음)
+		chapters := flagChapters
+		if chapters == nil && container != downloader.ContainerTS {
+			chapters = selectChapters(scanner)
+		}
+
 		// 구간 다운로드 관련 코드 제거 - HLS만 사용
 		fmt.Println("\n[알림] HLS 방식으로 전체 다운로드를 진행합니다.")
 		downloadSection := "" // 항상 전체 다운로드
 		speedOption := "100%" // 속도 옵션은 사용하지 않지만 기본값 유지
 
+		// 다운로드 백엔드 (-backend 옵션이 있으면 설정 대신 사용)
+		backendName := userSettings.Backend
+		if *backendFlag != "" {
+			backendName = *backendFlag
+		}
+
+		options := &downloader.DownloadOptions{
+			VodURL:           vodURL,
+			Quality:          selectedQuality,
+			OutputFolder:     outputFolder,
+			Filename:         autoFilename,
+			SpeedOption:      speedOption,
+			DownloadSection:  downloadSection,
+			Container:        container,
+			Profile:          profile,
+			Split:            split,
+			Chapters:         chapters,
+			Duplicate:        duplicatePolicy,
+			Prompter:         &consolePrompter{scanner: scanner},
+			NoArchive:        *noArchiveFlag,
+			MinFreeSpace:     minFreeSpace,
+			StallTimeout:     *stallTimeoutFlag,
+			MaxRestarts:      *maxRestartsFlag,
+			Backend:          backendName,
+			SegmentWorkers:   *workersFlag,
+			SkipLostSegments: *skipLostFlag,
+			JobID:            vodURL,
+			Title:            fullTitle,
+			Reporter:         reporter,
+		}
+
+		// 컨테이너에 맞춘 최종 파일 경로
+		outputFile, err := downloader.PrepareOutputPath(options)
+		if err != nil {
+			fmt.Printf("출력 경로 준비 중 오류 발생: %v\n", err)
+			continue
+		}
+		autoFilename = filepath.Base(outputFile)
+
 		// 최종 정보 확인 (개선된 UI)
 		fmt.Println("\n┌─────────────────────────────────────────────┐")
 		fmt.Println("│             다운로드 정보 확인               │")
@@ -420,6 +863,16 @@ func main() {
 
 		// 품질 정보 표시
 		fmt.Printf("│ 화질: %-40s │\n", selectedQualityName)
+		fmt.Printf("│ 출력 형식: %-35s │\n", container.Description())
+		if profile != nil {
+			fmt.Printf("│ 변환 프로필: %-33s │\n", profile.Name)
+		}
+		if split != nil {
+			fmt.Printf("│ 분할: %-40s │\n", split.String())
+		}
+		if len(chapters) > 0 {
+			fmt.Printf("│ 챕터: %-40s │\n", fmt.Sprintf("%d개", len(chapters)))
+		}
 
 		// 성인 컨텐츠 인증 정보 표시
 		if isAdultContent {
@@ -442,35 +895,84 @@ func main() {
 
 		// 다운로드 시작
 		fmt.Println("\n다운로드를 시작합니다. 잠시만 기다려주세요...")
-		outputFile := filepath.Join(outputFolder, autoFilename)
 
 		// 다운로드 시작 시간 기록
 		downloadStartTime := time.Now()
 
-		err = downloader.DownloadVOD(vodURL, selectedQuality, outputFolder, autoFilename, speedOption, downloadSection)
+		// 다운로드 중 Ctrl+C를 누르면 받은 부분까지 저장하고 중단
+		downloadCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		result, err := downloader.DownloadVOD(downloadCtx, options)
+		stopSignals()
+
+		// 예상 크기보다 여유 공간이 적으면 그래도 받을지 확인
+		var noSpace *downloader.InsufficientSpaceError
+		if errors.As(err, &noSpace) {
+			fmt.Print("\n예상 크기는 추정치입니다. 그래도 다운로드할까요? (y/N): ")
+			scanner.Scan()
+			if strings.ToLower(strings.TrimSpace(scanner.Text())) == "y" {
+				options.SkipSpaceCheck = true
+				downloadCtx, stopSignals = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				result, err = downloader.DownloadVOD(downloadCtx, options)
+				stopSignals()
+			}
+		}
 
 		// 다운로드 종료 시간으로 소요 시간 계산
 		elapsedTime := time.Since(downloadStartTime)
 
-		if err != nil {
+		var interrupted *downloader.InterruptedError
+		if errors.As(err, &interrupted) {
+			fmt.Println("\n┌─────────────────────────────────────────────┐")
+			fmt.Println("│             다운로드 중단됨                  │")
+			fmt.Println("├─────────────────────────────────────────────┤")
+			fmt.Printf("│ 파일명: %-38s │\n", filepath.Base(interrupted.OutputFile))
+			fmt.Printf("│ 저장된 길이: %-33s │\n", utils.SecondsToHms(int(interrupted.SavedSeconds)))
+			fmt.Printf("│ 저장된 크기: %-33s │\n", fmt.Sprintf("%.2f MB", float64(interrupted.SavedBytes)/(1024*1024)))
+			if interrupted.PlayableFile != "" {
+				fmt.Printf("│ 재생용 파일: %-33s │\n", filepath.Base(interrupted.PlayableFile))
+			}
+			fmt.Println("└─────────────────────────────────────────────┘")
+			if interrupted.SavedSeconds > 0 {
+				fmt.Println("같은 영상을 같은 화질로 다시 받으면 이어서 받습니다.")
+			}
+			fmt.Print("\n계속하려면 Enter를 누르세요.")
+			scanner.Scan()
+			continue
+		} else if err != nil {
 			fmt.Printf("\n❌ 다운로드 중 오류가 발생했습니다: %v\n", err)
 			fmt.Print("\n계속하려면 Enter를 누르세요.")
 			scanner.Scan()
 			continue
 		}
 
+		// 중복 파일이라 건너뛴 경우
+		if result.Skipped {
+			fmt.Print("\n계속하려면 Enter를 누르세요.")
+			scanner.Scan()
+			continue
+		}
+
 		// 성공 메시지 및 파일 정보 표시
-		fileInfo, _ := os.Stat(outputFile)
+		// 실제로 저장된 파일 기준 (이름 바꾸기로 저장했으면 바뀐 이름, 변환한 경우 변환된 파일)
+		finalFile := result.OutputFile
+		if result.TranscodedFile != "" {
+			finalFile = result.TranscodedFile
+		}
 		var fileSize int64
-		if fileInfo != nil {
+		if result.Parts != nil {
+			// 분할하면 원본은 삭제되므로 파트 크기의 합
+			for _, part := range result.Parts.Parts {
+				fileSize += part.Size
+			}
+		} else if fileInfo, err := os.Stat(finalFile); err == nil {
 			fileSize = fileInfo.Size()
 		}
 
 		fmt.Println("\n┌─────────────────────────────────────────────┐")
 		fmt.Println("│             다운로드 완료!                   │")
 		fmt.Println("├─────────────────────────────────────────────┤")
-		fmt.Printf("│ 파일명: %-38s │\n", autoFilename)
-		fmt.Printf("│ 저장 위치: %-35s │\n", outputFolder)
+		fmt.Printf("│ 파일명: %-38s │\n", filepath.Base(finalFile))
+		fmt.Printf("│ 저장 위치: %-35s │\n", filepath.Dir(finalFile))
 
 		if fileSize > 0 {
 			fileSizeStr := ""
@@ -484,6 +986,30 @@ func main() {
 			fmt.Printf("│ 파일 크기: %-35s │\n", fileSizeStr)
 		}
 
+		// 분할된 경우 파트 정보 표시
+		if result.Parts != nil {
+			fmt.Printf("│ 분할: %-40s │\n", fmt.Sprintf("%d개 파트", len(result.Parts.Parts)))
+			for _, part := range result.Parts.Parts {
+				fmt.Printf("│   %s  %-34s │\n", part.Start, part.File)
+			}
+			fmt.Printf("│ 목록: %-40s │\n", filepath.Base(downloader.SplitManifestPath(finalFile)))
+		}
+
+		// 받지 못해 건너뛴 세그먼트가 있으면 빠진 구간 표시
+		if len(result.Gaps) > 0 {
+			fmt.Printf("│ 빠진 구간: %-35s │\n", fmt.Sprintf("%d개 세그먼트, %.1f초", len(result.Gaps), downloader.TotalGapDuration(result.Gaps)))
+			for i, gap := range result.Gaps {
+				if i == maxSummaryGaps {
+					fmt.Printf("│   %-42s │\n", fmt.Sprintf("... 외 %d개", len(result.Gaps)-maxSummaryGaps))
+					break
+				}
+				fmt.Printf("│   %-42s │\n", gap.String())
+			}
+			if result.GapReport != "" {
+				fmt.Printf("│ 보고서: %-38s │\n", filepath.Base(result.GapReport))
+			}
+		}
+
 		// 다운로드 소요 시간 표시
 		elapsedMinutes := int(elapsedTime.Minutes())
 		elapsedSeconds := int(elapsedTime.Seconds()) % 60
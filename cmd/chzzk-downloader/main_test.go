@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chzzk-downloader/internal/downloader"
+	"chzzk-downloader/internal/setup"
+)
+
+// captureOutput 표준 출력과 표준 오류를 파이프로 바꿔 run을 실행하고 각각의 내용을 반환
+func captureOutput(t *testing.T, run func()) (stdout, stderr string) {
+	t.Helper()
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outW, errW
+	defer func() { os.Stdout, os.Stderr = oldStdout, oldStderr }()
+
+	outC := make(chan string)
+	errC := make(chan string)
+	go func() { data, _ := io.ReadAll(outR); outC <- string(data) }()
+	go func() { data, _ := io.ReadAll(errR); errC <- string(data) }()
+
+	run()
+
+	outW.Close()
+	errW.Close()
+	return <-outC, <-errC
+}
+
+func TestJSONProgressKeepsStdoutParseable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 4096)))
+	}))
+	defer server.Close()
+	dest := filepath.Join(t.TempDir(), "file.bin")
+
+	events := []downloader.ProgressEvent{
+		{Type: downloader.EventStarted, JobID: "job", Percent: -1, Message: "시작\nffmpeg CMD: ffmpeg -i pipe:0"},
+		{Type: downloader.EventProgress, JobID: "job", Percent: 50, CurrentBytes: 1024},
+		{Type: downloader.EventWarning, JobID: "job", Percent: -1, Message: "경고"},
+		{Type: downloader.EventCompleted, JobID: "job", Percent: 100},
+	}
+
+	stdout, stderr := captureOutput(t, func() {
+		reporter, err := newProgressReporter("json")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// 안내, 입력 요청, 요약처럼 사람이 읽는 출력과 의존성 설치 출력 사이에 이벤트를 섞음
+		fmt.Printf("==== 치지직 다운로더 (v%s) ====\n\n", VERSION)
+		reporter.Report(events[0])
+		fmt.Print("영상 주소 (예: https://chzzk.naver.com/video/1234567): ")
+		if _, err := setup.DownloadFile(context.Background(), server.URL+"/file.bin", dest); err != nil {
+			t.Error(err)
+		}
+		for _, event := range events[1:] {
+			reporter.Report(event)
+		}
+		fmt.Println("┌─────────────────────────────────────────────┐")
+		fmt.Println("│             다운로드 완료                    │")
+		fmt.Println("└─────────────────────────────────────────────┘")
+	})
+
+	var count int
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		var event downloader.ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Errorf("표준 출력에 JSON이 아닌 줄: %q (%v)", scanner.Text(), err)
+			continue
+		}
+		if event.Type != events[count].Type {
+			t.Errorf("이벤트 %d: %s, 기대 %s", count, event.Type, events[count].Type)
+		}
+		count++
+	}
+	if count != len(events) {
+		t.Errorf("이벤트 %d개, 기대 %d개", count, len(events))
+	}
+	for _, want := range []string{"치지직 다운로더", "영상 주소", "다운로드 시작", "다운로드 완료"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("표준 오류에 %q 없음:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestLineProgressKeepsStdout(t *testing.T) {
+	stdout, stderr := captureOutput(t, func() {
+		if _, err := newProgressReporter("line"); err != nil {
+			t.Error(err)
+		}
+		fmt.Println("안내")
+	})
+	if stdout != "안내\n" || stderr != "" {
+		t.Errorf("stdout %q, stderr %q", stdout, stderr)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/downloader"
+	"chzzk-downloader/internal/utils"
+)
+
+// merge 명령: 여러 VOD를 받아 하나의 파일로 합침
+func runMerge(args []string) int {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	auto := fs.Bool("auto", false, "주어진 VOD와 같은 날 같은 채널에서 방송된 다시보기를 자동으로 찾아 합침")
+	quality := fs.String("quality", "", "품질 이름 (예: 1080p, 비어 있으면 공통 최고 품질)")
+	outputFolder := fs.String("o", "", "저장 폴더 (비어 있으면 설정의 다운로드 폴더)")
+	filename := fs.String("name", "", "저장할 파일명 (비어 있으면 첫 VOD 정보로 생성)")
+	containerName := fs.String("container", "", "출력 형식 (mp4, fmp4, mkv, ts)")
+	chaptersFile := fs.String("chapters", "", "합친 파일 기준 타임스탬프 목록 파일 (텍스트 또는 .json)")
+	minFree := fs.String("min-free", "1GiB", "받는 중 여유 공간이 이보다 줄어들면 일시 정지 (0이면 사용 안 함)")
+	stallTimeout := fs.Duration("stall-timeout", 2*time.Minute, "이 시간 동안 진행이 없으면 마지막 저장 위치부터 다시 받음 (0이면 사용 안 함)")
+	maxRestarts := fs.Int("max-restarts", 3, "진행이 멈췄을 때 다시 받는 최대 횟수")
+	backend := fs.String("backend", "", "다운로드 백엔드 (auto, streamlink, hls, dash, ffmpeg)")
+	workers := fs.Int("segment-workers", downloader.DefaultSegmentWorkers, fmt.Sprintf("HLS 세그먼트를 동시에 받는 수 (1~%d)", downloader.MaxSegmentWorkers))
+	jobs := fs.Int("jobs", downloader.DefaultMergeJobs, fmt.Sprintf("VOD를 동시에 받는 수 (1~%d, 여러 개면 -progress multi 권장)", downloader.MaxMergeJobs))
+	skipLost := fs.Bool("skip-lost-segments", false, "끝내 받지 못한 세그먼트를 건너뛰고 계속 받음")
+	progressMode := fs.String("progress", "line", "진행 표시 방식 (line, multi, json)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "사용법: chzzk-downloader merge [옵션] <VOD URL> [VOD URL...]")
+		fmt.Fprintln(fs.Output(), "       chzzk-downloader merge -auto [옵션] <VOD URL>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	vodURLs := fs.Args()
+	if len(vodURLs) == 0 || (!*auto && len(vodURLs) < 2) {
+		fs.Usage()
+		return 2
+	}
+
+	reporter, err := newProgressReporter(*progressMode)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	container, err := downloader.ParseContainer(*containerName)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	if _, err := downloader.ParseBackend(*backend); err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	if *workers < 1 || *workers > downloader.MaxSegmentWorkers {
+		fmt.Printf("-segment-workers는 1~%d 사이여야 합니다\n", downloader.MaxSegmentWorkers)
+		return 2
+	}
+
+	if *jobs < 1 || *jobs > downloader.MaxMergeJobs {
+		fmt.Printf("-jobs는 1~%d 사이여야 합니다\n", downloader.MaxMergeJobs)
+		return 2
+	}
+
+	minFreeSpace, err := parseMinFree(*minFree)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+
+	var chapters []downloader.Chapter
+	if *chaptersFile != "" {
+		chapters, err = downloader.LoadChapterFile(*chaptersFile)
+		if err != nil {
+			fmt.Println(err)
+			return 2
+		}
+	}
+
+	if !ensureDependencies() {
+		return 1
+	}
+
+	// 저장 폴더 결정
+	folder := *outputFolder
+	if folder == "" {
+		settings, _ := config.LoadUserSettings()
+		folder = settings.DownloadFolder
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		fmt.Printf("폴더 생성 실패: %v\n", err)
+		return 1
+	}
+
+	// 연속된 VOD 자동 탐지
+	if *auto {
+		detected, err := downloader.DetectConsecutiveVODs(vodURLs[0])
+		if err != nil {
+			fmt.Printf("연속된 VOD를 찾는 중 오류 발생: %v\n", err)
+			return 1
+		}
+		vodURLs = detected
+		fmt.Printf("같은 날 방송된 VOD %d개를 찾았습니다:\n", len(vodURLs))
+		for i, vodURL := range vodURLs {
+			fmt.Printf("%d. %s\n", i+1, vodURL)
+		}
+		if len(vodURLs) < 2 {
+			fmt.Println("합칠 VOD가 하나뿐입니다.")
+			return 1
+		}
+	}
+
+	// 다운로드 중 Ctrl+C를 누르면 받은 부분까지 저장하고 중단
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := downloader.MergeVODs(ctx, &downloader.MergeOptions{
+		VodURLs:      vodURLs,
+		Quality:      *quality,
+		OutputFolder: folder,
+		Filename:     *filename,
+		Container:    container,
+		Chapters:     chapters,
+		MinFreeSpace: minFreeSpace,
+		StallTimeout: *stallTimeout,
+		MaxRestarts:  *maxRestarts,
+		Backend:      *backend,
+		Workers:      *workers,
+		Jobs:         *jobs,
+		SkipLost:     *skipLost,
+		Reporter:     reporter,
+	})
+
+	var interrupted *downloader.InterruptedError
+	if errors.As(err, &interrupted) {
+		fmt.Printf("\n병합이 중단되었습니다. 같은 명령을 다시 실행하면 '이어받기'로 계속할 수 있습니다.\n")
+		return 130
+	} else if err != nil {
+		fmt.Printf("\n❌ 병합 중 오류가 발생했습니다: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("\n==== 병합 완료 ====")
+	fmt.Printf("파일: %s\n", filepath.Base(result.OutputFile))
+	fmt.Printf("저장 위치: %s\n", filepath.Dir(result.OutputFile))
+	fmt.Printf("전체 길이: %s (%d개 VOD)\n", utils.SecondsToHms(result.Duration), len(vodURLs))
+	if len(result.Gaps) > 0 {
+		fmt.Printf("빠진 구간: %d개 세그먼트, %.1f초\n", len(result.Gaps), downloader.TotalGapDuration(result.Gaps))
+		for i, gap := range result.Gaps {
+			if i == maxSummaryGaps {
+				fmt.Printf("  ... 외 %d개\n", len(result.Gaps)-maxSummaryGaps)
+				break
+			}
+			fmt.Printf("  %s\n", gap)
+		}
+		if result.GapReport != "" {
+			fmt.Printf("보고서: %s\n", filepath.Base(result.GapReport))
+		}
+	}
+	return 0
+}
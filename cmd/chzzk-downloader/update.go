@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"chzzk-downloader/internal/update"
+)
+
+// update 명령: 새 릴리스 확인 및 실행 파일 교체
+func runUpdate(args []string) int {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	check := fs.Bool("check", false, "설치하지 않고 새 버전이 있는지만 확인")
+	apiURL := fs.String("api", update.DefaultAPIURL, "최신 릴리스 정보 주소 (GitHub releases API 형식)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "사용법: chzzk-downloader update [-check]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	updater := &update.Updater{APIURL: *apiURL, Current: VERSION}
+	release, newer, err := updater.Check(ctx)
+	if err != nil {
+		fmt.Printf("업데이트 확인 실패: %v\n", err)
+		return 1
+	}
+
+	latest := strings.TrimPrefix(release.TagName, "v")
+	if !newer {
+		fmt.Printf("최신 버전입니다 (v%s, 최신 릴리스 v%s)\n", VERSION, latest)
+		return 0
+	}
+
+	fmt.Printf("새 버전이 있습니다: v%s -> v%s\n", VERSION, latest)
+	if release.HTMLURL != "" {
+		fmt.Printf("릴리스 노트: %s\n", release.HTMLURL)
+	}
+	if *check {
+		return 0
+	}
+
+	exePath, err := update.ExecutablePath()
+	if err != nil {
+		fmt.Printf("실행 파일 위치를 찾을 수 없습니다: %v\n", err)
+		return 1
+	}
+	if err := updater.Apply(ctx, release, exePath); err != nil {
+		fmt.Printf("업데이트 실패: %v\n", err)
+		return 1
+	}
+	fmt.Printf("v%s로 업데이트했습니다. 다시 실행하면 새 버전이 적용됩니다.\n", latest)
+	return 0
+}
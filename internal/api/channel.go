@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"chzzk-downloader/internal/config"
+)
+
+const (
+	ChzzkChannelVideosAPI = "https://api.chzzk.naver.com/service/v1/channels/%s/videos?sortType=LATEST&pagingType=PAGE&page=%d&size=%d"
+)
+
+// VideoSummary 채널 영상 목록의 영상 정보 구조체
+type VideoSummary struct {
+	VideoNo      int    `json:"videoNo"`
+	VideoTitle   string `json:"videoTitle"`
+	VideoType    string `json:"videoType"` // REPLAY, UPLOAD
+	PublishDate  string `json:"publishDate"`
+	LiveOpenDate string `json:"liveOpenDate"`
+	Duration     int    `json:"duration"`
+}
+
+// channelVideosResponse 채널 영상 목록 API 응답 구조체
+type channelVideosResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Content struct {
+		Page       int            `json:"page"`
+		TotalPages int            `json:"totalPages"`
+		Data       []VideoSummary `json:"data"`
+	} `json:"content"`
+}
+
+// GetChannelVideos 채널의 영상 목록을 최신순으로 가져오는 함수
+func GetChannelVideos(channelID string, page, size int) ([]VideoSummary, error) {
+	apiURL := fmt.Sprintf(ChzzkChannelVideosAPI, channelID, page, size)
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range config.GetCookieHeaders() {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var videosResp channelVideosResponse
+	if err := json.Unmarshal(body, &videosResp); err != nil {
+		return nil, err
+	}
+
+	if videosResp.Code != 200 {
+		return nil, fmt.Errorf("채널 영상 목록 API 오류: %s", videosResp.Message)
+	}
+
+	return videosResp.Content.Data, nil
+}
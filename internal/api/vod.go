@@ -16,6 +16,7 @@ import (
 const (
 	ChzzkVodInfoAPI = "https://api.chzzk.naver.com/service/v2/videos/%s"
 	ChzzkVodUriAPI  = "https://apis.naver.com/neonplayer/vodplay/v2/playback/%s?key=%s"
+	ChzzkVideoURL   = "https://chzzk.naver.com/video/%d"
 )
 
 // Quality 품질 정보 구조체
@@ -31,16 +32,19 @@ type Quality struct {
 
 // VodInfo 치지직 VOD 정보 구조체
 type VodInfo struct {
+	VideoNo      int         `json:"videoNo"`
 	VideoTitle   string      `json:"videoTitle"`
 	VideoID      string      `json:"videoId"`
 	InKey        string      `json:"inKey"`
 	LiveOpenDate string      `json:"liveOpenDate"`
 	VodStatus    string      `json:"vodStatus"`
+	Duration     int         `json:"duration"` // 영상 길이 (초)
 	Channel      ChannelInfo `json:"channel"`
 }
 
 // ChannelInfo 채널 정보 구조체
 type ChannelInfo struct {
+	ChannelID   string `json:"channelId"`
 	ChannelName string `json:"channelName"`
 }
 
@@ -387,3 +391,14 @@ func GetVODUrl(vodURL string, quality string) (string, error) {
 		return "", errors.New("원하는 품질의 BaseURL을 찾을 수 없습니다")
 	}
 }
+
+// VideoNoFromURL VOD URL에서 영상 번호를 추출하는 함수
+func VideoNoFromURL(vodURL string) string {
+	parts := strings.Split(strings.TrimRight(vodURL, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+// VideoURL 영상 번호로 VOD URL을 만드는 함수
+func VideoURL(videoNo int) string {
+	return fmt.Sprintf(ChzzkVideoURL, videoNo)
+}
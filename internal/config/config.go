@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -12,6 +13,17 @@ import (
 const (
 	CookieFileName   = "cookie.json"
 	UserSettingsFile = "settings.json"
+
+	TranscodeProfilesFile = "profiles.json"
+	DownloadArchiveFile   = "archive.json"
+
+	DependencyManifestFile = "dependencies.json"
+	FFmpegCapabilitiesFile = "ffmpeg-capabilities.json"
+
+	// 실행 파일 옆에 이 파일이 있으면 포터블 모드
+	PortableMarkerFile = "portable"
+	// 이 환경 변수가 1이나 true면 포터블 모드
+	PortableEnv = "CHZZK_DOWNLOADER_PORTABLE"
 )
 
 // RecentVodInfo 최근 VOD 정보를 저장하는 구조체
@@ -30,37 +42,47 @@ type UserSettings struct {
 	LastVodURL      string          `json:"lastVodURL"`    // 마지막으로 다운로드한 VOD URL
 	RecentVodURLs   []string        `json:"recentVodURLs"` // 하위 호환성을 위해 유지
 	RecentVods      []RecentVodInfo `json:"recentVods"`    // 최근 다운로드한 VOD 정보 목록 (URL과 제목)
+	Container       string          `json:"container"`     // 기본 출력 컨테이너 (mp4, fmp4, mkv, ts)
+	Backend         string          `json:"backend"`       // 다운로드 백엔드 (auto, streamlink, hls, dash, ffmpeg)
 }
 
-// GetBaseDir 현재 실행 파일의 디렉토리 경로를 반환
-func GetBaseDir() string {
-	exePath, err := os.Executable()
-	if err != nil {
-		// 오류가 발생하면 현재 작업 디렉토리를 사용
-		wd, _ := os.Getwd()
-		return wd
-	}
-	return filepath.Dir(exePath)
+// 의존성 파일들 경로 반환 함수들
+// 설치한 파일이 있으면 그 파일을, 없으면 PATH에 있는 실행 파일을 사용
+// 둘 다 없으면 설치할 경로를 반환
+func GetFFmpeg() string {
+	return findBinary("ffmpeg")
 }
 
-// GetDependentDir 의존성 파일들이 있는 디렉토리 경로를 반환
-func GetDependentDir() string {
-	return filepath.Join(GetBaseDir(), "dependent")
+func GetStreamlink() string {
+	return findBinary("streamlink")
 }
 
-// 의존성 파일들 경로 반환 함수들
-func GetFFmpeg() string {
+// BundledBinaryPath 의존성 폴더에 설치되는 실행 파일 경로 (dependent/<이름>/bin/<이름>)
+func BundledBinaryPath(name string) string {
+	return filepath.Join(GetDependentDir(), name, "bin", ExecutableName(name))
+}
+
+// ExecutableName 운영체제에 맞는 실행 파일 이름 (Windows는 .exe)
+func ExecutableName(name string) string {
 	if runtime.GOOS == "windows" {
-		return filepath.Join(GetDependentDir(), "ffmpeg", "bin", "ffmpeg.exe")
+		return name + ".exe"
 	}
-	return filepath.Join(GetDependentDir(), "ffmpeg", "bin", "ffmpeg")
+	return name
 }
 
-func GetStreamlink() string {
-	if runtime.GOOS == "windows" {
-		return filepath.Join(GetDependentDir(), "streamlink", "bin", "streamlink.exe")
+// findBinary 설치한 실행 파일, PATH의 실행 파일 순서로 찾는 함수
+func findBinary(name string) string {
+	bundled := BundledBinaryPath(name)
+	if _, err := os.Stat(bundled); err == nil {
+		return bundled
 	}
-	return filepath.Join(GetDependentDir(), "streamlink", "bin", "streamlink")
+	if path, err := exec.LookPath(name); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return path
+	}
+	return bundled
 }
 
 // AddRecentVod 최근 VOD 목록에 VOD 정보를 추가하는 함수
@@ -131,7 +153,7 @@ func AddRecentURL(settings *UserSettings, url string) {
 func LoadUserSettings() (UserSettings, error) {
 	settingsFile := filepath.Join(GetBaseDir(), UserSettingsFile)
 	settings := UserSettings{
-		DownloadFolder: filepath.Join(GetBaseDir(), "downloads"), // 기본값 설정
+		DownloadFolder: GetDefaultDownloadDir(), // 기본값 설정
 	}
 
 	data, err := os.ReadFile(settingsFile)
@@ -163,13 +185,17 @@ func LoadUserSettings() (UserSettings, error) {
 // SaveUserSettings 사용자 설정을 저장하는 함수
 func SaveUserSettings(settings UserSettings) error {
 	settingsFile := filepath.Join(GetBaseDir(), UserSettingsFile)
+	if err := os.MkdirAll(GetBaseDir(), 0755); err != nil {
+		return err
+	}
 
 	data, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(settingsFile, data, 0644)
+	// 성인 인증 쿠키가 들어 있으므로 본인만 읽을 수 있게 저장
+	return os.WriteFile(settingsFile, data, 0600)
 }
 
 // UpdateUserSettings 사용자 설정의 특정 필드만 업데이트하는 함수
@@ -186,7 +212,7 @@ func UpdateUserSettings(updateFn func(*UserSettings)) error {
 
 // LoadCookies 쿠키 파일을 로드하는 함수
 func LoadCookies() map[string]string {
-	cookieFile := filepath.Join(GetDependentDir(), CookieFileName)
+	cookieFile := filepath.Join(GetBaseDir(), CookieFileName)
 	cookies := make(map[string]string)
 
 	data, err := os.ReadFile(cookieFile)
@@ -203,10 +229,10 @@ func LoadCookies() map[string]string {
 
 // SaveCookies 쿠키 파일을 저장하는 함수
 func SaveCookies(cookies map[string]string) error {
-	cookieFile := filepath.Join(GetDependentDir(), CookieFileName)
+	cookieFile := filepath.Join(GetBaseDir(), CookieFileName)
 
-	// dependent 디렉토리가 없으면 생성
-	if err := os.MkdirAll(GetDependentDir(), 0755); err != nil {
+	// 설정 디렉토리가 없으면 생성
+	if err := os.MkdirAll(GetBaseDir(), 0755); err != nil {
 		return err
 	}
 
@@ -215,7 +241,7 @@ func SaveCookies(cookies map[string]string) error {
 		return err
 	}
 
-	return os.WriteFile(cookieFile, data, 0644)
+	return os.WriteFile(cookieFile, data, 0600)
 }
 
 // SetAdultCookies 성인 인증 쿠키를 설정하는 함수
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// 사용자 폴더 아래에 만드는 폴더 이름
+const appDirName = "chzzk-downloader"
+
+// -portable 옵션으로 켠 포터블 모드
+var portableFlag bool
+
+// SetPortable 포터블 모드 설정 (-portable 옵션)
+func SetPortable(portable bool) {
+	portableFlag = portable
+}
+
+// IsPortable 설정과 데이터를 실행 파일 옆에 두는 포터블 모드인지 여부
+// -portable 옵션, CHZZK_DOWNLOADER_PORTABLE 환경 변수, 실행 파일 옆의 portable 파일 중 하나면 켜지며
+// 사용자 폴더를 알 수 없는 환경에서도 켜짐
+func IsPortable() bool {
+	if portableFlag {
+		return true
+	}
+	switch strings.ToLower(os.Getenv(PortableEnv)) {
+	case "1", "true", "yes":
+		return true
+	}
+	if _, err := os.Stat(filepath.Join(GetExecutableDir(), PortableMarkerFile)); err == nil {
+		return true
+	}
+	_, err := os.UserConfigDir()
+	return err != nil
+}
+
+// GetExecutableDir 현재 실행 파일의 디렉토리 경로를 반환
+func GetExecutableDir() string {
+	exePath, err := os.Executable()
+	if err != nil {
+		// 오류가 발생하면 현재 작업 디렉토리를 사용
+		wd, _ := os.Getwd()
+		return wd
+	}
+	return filepath.Dir(exePath)
+}
+
+// GetBaseDir 설정 파일(settings.json, cookie.json, profiles.json 등)을 두는 디렉토리
+// 포터블 모드면 실행 파일 옆, 아니면 사용자 설정 폴더 (~/.config/chzzk-downloader, %AppData%\chzzk-downloader 등)
+func GetBaseDir() string {
+	if IsPortable() {
+		return GetExecutableDir()
+	}
+	dir, _ := os.UserConfigDir()
+	return filepath.Join(dir, appDirName)
+}
+
+// GetDataDir 설치한 의존성, 다운로드 기록, 임시 파일을 두는 디렉토리
+// 포터블 모드면 실행 파일 옆, 아니면 $XDG_DATA_HOME(~/.local/share), %LocalAppData%, ~/Library/Application Support 아래
+func GetDataDir() string {
+	if IsPortable() {
+		return GetExecutableDir()
+	}
+
+	var dir string
+	switch runtime.GOOS {
+	case "windows":
+		dir = os.Getenv("LocalAppData")
+	case "darwin", "ios":
+		dir, _ = os.UserConfigDir()
+	default:
+		dir = os.Getenv("XDG_DATA_HOME")
+		if !filepath.IsAbs(dir) {
+			dir = ""
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, ".local", "share")
+			}
+		}
+	}
+	if dir == "" {
+		return GetBaseDir()
+	}
+	return filepath.Join(dir, appDirName)
+}
+
+// GetCacheDir 지워도 다시 만들 수 있는 파일(ffmpeg 기능 확인 결과 등)을 두는 디렉토리
+func GetCacheDir() string {
+	if IsPortable() {
+		return GetExecutableDir()
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return GetDataDir()
+	}
+	return filepath.Join(dir, appDirName)
+}
+
+// GetDependentDir 의존성 파일들이 있는 디렉토리 경로를 반환
+func GetDependentDir() string {
+	return filepath.Join(GetDataDir(), "dependent")
+}
+
+// GetDefaultDownloadDir 다운로드 폴더 기본값
+// 포터블 모드면 실행 파일 옆 downloads, 아니면 사용자 다운로드 폴더 아래 chzzk-downloader
+func GetDefaultDownloadDir() string {
+	if IsPortable() {
+		return filepath.Join(GetExecutableDir(), "downloads")
+	}
+	dir := os.Getenv("XDG_DOWNLOAD_DIR")
+	if !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(GetDataDir(), "downloads")
+		}
+		dir = filepath.Join(home, "Downloads")
+	}
+	return filepath.Join(dir, appDirName)
+}
+
+// Migration 실행 파일 옆에서 사용자 폴더로 옮긴 파일 하나
+type Migration struct {
+	From string
+	To   string
+	Err  error // 복사는 했지만 원래 파일을 지우지 못한 경우 등
+}
+
+// MigrateLegacyFiles 이전 버전이 실행 파일 옆에 만든 설정, 쿠키, 기록, 의존성을 사용자 폴더로 옮김
+// 포터블 모드이거나 옮길 곳에 이미 파일이 있으면 건너뛰며, 옮긴 항목 목록을 반환
+// 원래 위치가 읽기 전용이면 복사만 하고 원본은 남김 (다음 실행에서는 옮길 곳에 파일이 있으므로 다시 옮기지 않음)
+func MigrateLegacyFiles() ([]Migration, error) {
+	if IsPortable() {
+		return nil, nil
+	}
+	oldDir := GetExecutableDir()
+	if sameDir(oldDir, GetBaseDir()) || sameDir(oldDir, GetDataDir()) {
+		return nil, nil
+	}
+
+	// private: 쿠키가 들어 있어 본인만 읽을 수 있게 할 파일
+	items := []struct {
+		from, to string
+		private  bool
+	}{
+		{filepath.Join(oldDir, UserSettingsFile), filepath.Join(GetBaseDir(), UserSettingsFile), true},
+		{filepath.Join(oldDir, "dependent", CookieFileName), filepath.Join(GetBaseDir(), CookieFileName), true},
+		{filepath.Join(oldDir, TranscodeProfilesFile), filepath.Join(GetBaseDir(), TranscodeProfilesFile), false},
+		{filepath.Join(oldDir, DownloadArchiveFile), filepath.Join(GetDataDir(), DownloadArchiveFile), false},
+		{filepath.Join(oldDir, "dependent"), GetDependentDir(), false},
+	}
+
+	var migrations []Migration
+	for _, item := range items {
+		if _, err := os.Lstat(item.from); err != nil {
+			continue
+		}
+		if _, err := os.Lstat(item.to); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(item.to), 0755); err != nil {
+			return migrations, err
+		}
+		err := movePath(item.from, item.to)
+		if err != nil && !isCopied(err) {
+			return migrations, fmt.Errorf("%s를 %s로 옮기지 못했습니다: %v", item.from, item.to, err)
+		}
+		if item.private {
+			os.Chmod(item.to, 0600)
+		}
+		migrations = append(migrations, Migration{From: item.from, To: item.to, Err: err})
+	}
+	return migrations, nil
+}
+
+// copiedError 복사는 끝났지만 원본을 지우지 못함
+type copiedError struct{ err error }
+
+func (e *copiedError) Error() string {
+	return fmt.Sprintf("복사했지만 원래 파일을 지우지 못했습니다: %v", e.err)
+}
+
+func isCopied(err error) bool {
+	_, ok := err.(*copiedError)
+	return ok
+}
+
+// movePath 파일이나 폴더를 옮김 (다른 드라이브라 이름을 바꿀 수 없으면 복사 후 원본 삭제)
+func movePath(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	// 복사 도중 실패하면 반쯤 복사된 결과를 남기지 않도록 임시 이름에 복사한 뒤 바꿈
+	tmp := to + ".migrating"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := copyTree(from, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, to); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.RemoveAll(from); err != nil {
+		return &copiedError{err}
+	}
+	return nil
+}
+
+// copyTree 파일 또는 폴더 전체를 권한을 유지해 복사
+func copyTree(from, to string) error {
+	return filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sameDir 두 경로가 같은 폴더인지 확인
+func sameDir(a, b string) bool {
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(ai, bi)
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,277 @@
+package downloader
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/config"
+)
+
+// ArchiveRecord 다운로드 기록 하나 (영상 번호당 하나)
+type ArchiveRecord struct {
+	VideoNo      int       `json:"videoNo"`
+	VodURL       string    `json:"vodURL"`
+	Title        string    `json:"title"`
+	Channel      string    `json:"channel"`
+	Quality      string    `json:"quality"` // 품질 이름 (예: 1080p)
+	Height       int       `json:"height"`  // 세로 해상도 (화질 비교에 사용)
+	Path         string    `json:"path"`    // 저장된 파일 (분할한 경우 파트 목록 파일)
+	Size         int64     `json:"size"`    // 파일 크기 (분할한 경우 모든 파트 합계)
+	SHA256       string    `json:"sha256"`  // 파일 체크섬 (분할한 경우 비어 있음)
+	DownloadedAt time.Time `json:"downloadedAt"`
+}
+
+// Archive 다운로드 기록 목록 (archive.json)
+type Archive struct {
+	Records []ArchiveRecord `json:"records"`
+}
+
+// ArchivePath 다운로드 기록 파일 경로
+func ArchivePath() string {
+	return filepath.Join(config.GetDataDir(), config.DownloadArchiveFile)
+}
+
+// LoadArchive 다운로드 기록을 불러오는 함수 (파일이 없으면 빈 기록 반환)
+func LoadArchive() (*Archive, error) {
+	archive := &Archive{}
+
+	data, err := os.ReadFile(ArchivePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return archive, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, fmt.Errorf("다운로드 기록 파싱 실패: %v", err)
+	}
+
+	return archive, nil
+}
+
+// SaveArchive 다운로드 기록을 저장하는 함수
+// 저장 도중 종료되어도 기존 기록이 깨지지 않도록 임시 파일에 쓴 뒤 교체
+func SaveArchive(archive *Archive) error {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := ArchivePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, path)
+}
+
+// UpdateArchive 다운로드 기록을 불러와 수정한 뒤 저장하는 함수
+func UpdateArchive(updateFn func(*Archive)) error {
+	archive, err := LoadArchive()
+	if err != nil {
+		return err
+	}
+
+	updateFn(archive)
+
+	return SaveArchive(archive)
+}
+
+// Find 영상 번호로 기록을 찾는 함수 (없으면 nil)
+func (a *Archive) Find(videoNo int) *ArchiveRecord {
+	for i := range a.Records {
+		if a.Records[i].VideoNo == videoNo {
+			return &a.Records[i]
+		}
+	}
+	return nil
+}
+
+// Put 기록을 추가하거나 같은 영상 번호의 기록을 교체하는 함수
+func (a *Archive) Put(record ArchiveRecord) {
+	if existing := a.Find(record.VideoNo); existing != nil {
+		*existing = record
+		return
+	}
+	a.Records = append(a.Records, record)
+}
+
+// Sorted 최근에 받은 순서로 정렬한 기록 목록
+func (a *Archive) Sorted() []ArchiveRecord {
+	records := append([]ArchiveRecord(nil), a.Records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].DownloadedAt.After(records[j].DownloadedAt)
+	})
+	return records
+}
+
+// Search 제목, 채널명, 영상 번호에 검색어가 포함된 기록을 찾는 함수 (대소문자 무시)
+func (a *Archive) Search(query string) []ArchiveRecord {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var results []ArchiveRecord
+	for _, record := range a.Sorted() {
+		if strings.Contains(strings.ToLower(record.Title), query) ||
+			strings.Contains(strings.ToLower(record.Channel), query) ||
+			strings.Contains(strconv.Itoa(record.VideoNo), query) {
+			results = append(results, record)
+		}
+	}
+	return results
+}
+
+// PruneOptions 기록 정리 조건
+type PruneOptions struct {
+	Missing   bool          // 파일이 없어진 기록 삭제
+	OlderThan time.Duration // 이 기간보다 오래된 기록 삭제 (0이면 사용 안 함)
+}
+
+// Prune 조건에 맞는 기록을 삭제하고 삭제한 기록을 반환하는 함수
+func (a *Archive) Prune(options PruneOptions) []ArchiveRecord {
+	var kept, removed []ArchiveRecord
+	for _, record := range a.Records {
+		expired := options.OlderThan > 0 && time.Since(record.DownloadedAt) > options.OlderThan
+		missing := options.Missing && !record.Exists()
+		if expired || missing {
+			removed = append(removed, record)
+		} else {
+			kept = append(kept, record)
+		}
+	}
+	a.Records = kept
+	return removed
+}
+
+// Exists 기록된 파일이 아직 있는지 확인 (분할한 경우 목록 파일과 모든 파트)
+func (r *ArchiveRecord) Exists() bool {
+	if _, err := os.Stat(r.Path); err != nil {
+		return false
+	}
+	if !strings.HasSuffix(r.Path, splitManifestSuffix) {
+		return true
+	}
+
+	// 분할한 경우 목록 파일에 적힌 파트가 모두 남아 있어야 함
+	manifest, err := loadSplitManifest(r.Path)
+	if err != nil || len(manifest.Parts) == 0 {
+		return false
+	}
+	for _, part := range manifest.Parts {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(r.Path), part.File)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// archiveCSVHeader CSV로 내보낼 때의 열 이름
+var archiveCSVHeader = []string{"videoNo", "vodURL", "title", "channel", "quality", "height", "path", "size", "sha256", "downloadedAt"}
+
+// ExportCSV 기록을 CSV로 내보내는 함수
+func (a *Archive) ExportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(archiveCSVHeader); err != nil {
+		return err
+	}
+
+	for _, record := range a.Sorted() {
+		row := []string{
+			strconv.Itoa(record.VideoNo),
+			record.VodURL,
+			record.Title,
+			record.Channel,
+			record.Quality,
+			strconv.Itoa(record.Height),
+			record.Path,
+			strconv.FormatInt(record.Size, 10),
+			record.SHA256,
+			record.DownloadedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportJSON 기록을 JSON 배열로 내보내는 함수
+func (a *Archive) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(a.Sorted())
+}
+
+// fileSHA256 파일의 SHA-256 체크섬 계산
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// newArchiveRecord 다운로드 결과로 기록을 만드는 함수
+func newArchiveRecord(result *DownloadResult, vodURL string, videoNo int, title, channel, quality string, height int) (ArchiveRecord, error) {
+	record := ArchiveRecord{
+		VideoNo:      videoNo,
+		VodURL:       vodURL,
+		Title:        title,
+		Channel:      channel,
+		Quality:      quality,
+		Height:       height,
+		DownloadedAt: time.Now(),
+	}
+
+	// 분할한 경우 파트 목록 파일을 기록하고 크기는 파트 합계로 계산
+	if result.Parts != nil {
+		source := result.OutputFile
+		if result.TranscodedFile != "" {
+			source = result.TranscodedFile
+		}
+		record.Path = SplitManifestPath(source)
+		for _, part := range result.Parts.Parts {
+			record.Size += part.Size
+		}
+		return record, nil
+	}
+
+	record.Path = result.OutputFile
+	if result.TranscodedFile != "" {
+		record.Path = result.TranscodedFile
+	}
+
+	fileStat, err := os.Stat(record.Path)
+	if err != nil {
+		return record, err
+	}
+	record.Size = fileStat.Size()
+
+	record.SHA256, err = fileSHA256(record.Path)
+	if err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
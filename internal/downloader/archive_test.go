@@ -0,0 +1,48 @@
+package downloader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRecordExistsChecksSplitParts(t *testing.T) {
+	dir := t.TempDir()
+	manifest := SplitManifest{Source: "video.mp4", Parts: []SplitPart{
+		{Index: 1, File: "video_part001.mp4"},
+		{Index: 2, File: "video_part002.mp4"},
+	}}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := SplitManifestPath(filepath.Join(dir, "video.mp4"))
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, part := range manifest.Parts {
+		if err := os.WriteFile(filepath.Join(dir, part.File), []byte("part"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record := &ArchiveRecord{Path: manifestPath}
+	if !record.Exists() {
+		t.Fatal("all parts present, want Exists")
+	}
+
+	if err := os.Remove(filepath.Join(dir, manifest.Parts[1].File)); err != nil {
+		t.Fatal(err)
+	}
+	if record.Exists() {
+		t.Error("part 2 removed, want not Exists")
+	}
+
+	if err := os.WriteFile(manifestPath, []byte(`{"source":"video.mp4","parts":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if record.Exists() {
+		t.Error("manifest without parts, want not Exists")
+	}
+}
@@ -0,0 +1,204 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/api"
+	"chzzk-downloader/internal/config"
+)
+
+// StreamType VOD 스트림 형식
+type StreamType string
+
+const (
+	StreamHLS  StreamType = "hls"  // liveRewindPlaybackJson의 HLS 재생목록
+	StreamDASH StreamType = "dash" // inKey로 받는 MPD의 화질별 MP4 (BaseURL)
+)
+
+// Stream 다운로드할 스트림 정보
+type Stream struct {
+	Type     StreamType
+	URL      string      // HLS: 마스터 재생목록, DASH: 선택한 화질의 BaseURL
+	Quality  api.Quality // 선택한 화질
+	Duration int         // 영상 길이 (초)
+}
+
+// Backend 스트림을 받아 파일로 저장하는 방법
+type Backend interface {
+	// Name 설정과 -backend 옵션에 사용하는 이름
+	Name() string
+	// Supports 이 스트림을 주어진 옵션으로 받을 수 있는지 여부
+	Supports(stream *Stream, options *DownloadOptions) bool
+	// Resolve 이 백엔드가 입력으로 사용할 주소 결정 (예: 마스터 재생목록에서 화질별 재생목록 선택)
+	Resolve(ctx context.Context, stream *Stream) (string, error)
+	// Download Resolve한 주소에서 job.StartOffset 위치부터 job.TargetFile에 저장
+	Download(ctx context.Context, job *transferJob) (transferResult, error)
+}
+
+// byteResumer 기존 파일 뒤에 바이트 단위로 이어 쓰는 백엔드
+// 이어받은 부분을 별도 파일로 받아 합치지 않고 같은 파일에 이어서 기록함
+type byteResumer interface {
+	resumesByBytes() bool
+}
+
+// resumesByBytes 백엔드가 바이트 단위로 이어받는지 여부
+func resumesByBytes(backend Backend) bool {
+	if r, ok := backend.(byteResumer); ok {
+		return r.resumesByBytes()
+	}
+	return false
+}
+
+// transferJob 백엔드 한 번 실행에 필요한 정보
+type transferJob struct {
+	URL         string  // Resolve 결과
+	Stream      *Stream // 원본 스트림 정보
+	TargetFile  string  // 저장할 파일 (.part)
+	StartOffset float64 // 이 위치(초)부터 받음 (시간 단위로 이어받는 경우)
+	StartBytes  int64   // 이 위치(바이트)부터 이어 씀 (바이트 단위로 이어받는 경우)
+	BaseBytes   int64   // 진행 표시에 더할 기존 파일 크기
+	Options     *DownloadOptions
+	Report      *jobReporter
+}
+
+// transferResult 백엔드 한 번 실행 결과
+type transferResult struct {
+	outTime  float64 // 이번 실행에서 저장된 미디어 길이 (초)
+	size     int64   // 이번 실행에서 저장된 파일 크기
+	elapsed  time.Duration
+	gaps     []SegmentGap // 받지 못해 건너뛴 세그먼트
+	playable string       // 중단했을 때 받은 부분을 재생할 수 있게 따로 저장한 파일 (.part를 그대로 재생할 수 없는 경우)
+}
+
+// gapTracker 받지 못해 건너뛴 세그먼트를 알려주는 입력
+type gapTracker interface {
+	Gaps() []SegmentGap
+}
+
+// resumeAligner 이어받기 시작 위치를 세그먼트 경계에 맞추느라 앞부분을 건너뛴 입력
+// 건너뛴 길이만큼 저장된 위치를 늘려야 다음 이어받기 위치가 맞음
+type resumeAligner interface {
+	SkippedSeconds() float64
+}
+
+// BackendAuto 스트림 형식에 맞는 백엔드를 순서대로 시도
+const BackendAuto = "auto"
+
+// Backends 사용 가능한 백엔드 목록 (자동 선택 시 이 순서로 시도)
+var Backends = []Backend{
+	&streamlinkBackend{},
+	&hlsBackend{},
+	&dashBackend{},
+	&ffmpegBackend{},
+}
+
+// BackendNames 백엔드 이름 목록 (auto 포함)
+func BackendNames() []string {
+	names := []string{BackendAuto}
+	for _, b := range Backends {
+		names = append(names, b.Name())
+	}
+	return names
+}
+
+// ParseBackend 백엔드 이름 확인 (빈 문자열은 auto)
+func ParseBackend(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return BackendAuto, nil
+	}
+	for _, n := range BackendNames() {
+		if n == name {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("지원하지 않는 백엔드입니다: %s (%s)", name, strings.Join(BackendNames(), ", "))
+}
+
+// SelectBackends 스트림을 받을 백엔드 목록을 고르는 함수
+// auto면 스트림을 지원하는 백엔드를 모두 우선순위대로, 이름을 지정하면 그 백엔드만 반환
+func SelectBackends(name string, stream *Stream, options *DownloadOptions) ([]Backend, error) {
+	name, err := ParseBackend(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Backend
+	var capabilityErr error
+	for _, b := range Backends {
+		if name != BackendAuto && b.Name() != name {
+			continue
+		}
+		if !b.Supports(stream, options) {
+			if name != BackendAuto {
+				return nil, fmt.Errorf("%s 백엔드는 이 스트림(%s)을 받을 수 없습니다", name, stream.Type)
+			}
+			continue
+		}
+		// ffmpeg에 백엔드 입력을 읽을 기능이 없으면 자동 선택에서 제외
+		if err := checkFFmpegRequirements(backendRequirements(b.Name(), stream)); err != nil {
+			if name != BackendAuto {
+				return nil, err
+			}
+			if capabilityErr == nil {
+				capabilityErr = err
+			}
+			continue
+		}
+		selected = append(selected, b)
+	}
+
+	if len(selected) == 0 {
+		if capabilityErr != nil {
+			return nil, capabilityErr
+		}
+		return nil, fmt.Errorf("이 스트림(%s)을 받을 수 있는 백엔드가 없습니다", stream.Type)
+	}
+	return selected, nil
+}
+
+// ResolveStream VOD 정보와 선택한 화질로 받을 스트림을 정하는 함수
+func ResolveStream(vodURL string, vodInfo api.VodInfo, quality api.Quality) (*Stream, error) {
+	stream := &Stream{Quality: quality, Duration: vodInfo.Duration}
+
+	// inKey가 있으면 DASH, 없으면 HLS
+	if vodInfo.InKey != "" {
+		stream.Type = StreamDASH
+		stream.URL = quality.BaseURL
+		if stream.URL == "" {
+			return nil, fmt.Errorf("선택한 화질(%s)의 주소가 없습니다", quality.Quality)
+		}
+		return stream, nil
+	}
+
+	hlsURL, err := api.GetVODUrl(vodURL, "")
+	if err != nil {
+		return nil, err
+	}
+	stream.Type = StreamHLS
+	stream.URL = hlsURL
+	return stream, nil
+}
+
+// newMediaRequest 재생목록이나 미디어를 받기 위한 HTTP 요청
+func newMediaRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 로그인 쿠키는 치지직 API에만 필요하므로 미디어 서버에는 보내지 않음
+	headers := config.GetCookieHeaders()
+	req.Header.Set("User-Agent", headers["User-Agent"])
+	req.Header.Set("Referer", headers["Referer"])
+	return req, nil
+}
+
+// mediaHeaders ffmpeg -headers 옵션 값 (User-Agent, Referer)
+func mediaHeaders() string {
+	headers := config.GetCookieHeaders()
+	return fmt.Sprintf("User-Agent: %s\r\nReferer: %s\r\n", headers["User-Agent"], headers["Referer"])
+}
@@ -0,0 +1,269 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"chzzk-downloader/internal/config"
+)
+
+// dashBackend DASH 화질별 MP4 파일(BaseURL)을 HTTP로 직접 받는 백엔드
+// 받은 바이트 뒤에 Range 요청으로 이어서 받음
+type dashBackend struct{}
+
+func (b *dashBackend) Name() string { return "dash" }
+
+// Supports 파일을 그대로 받으므로 받으면서 변환하는 프로필은 지원하지 않음
+func (b *dashBackend) Supports(stream *Stream, options *DownloadOptions) bool {
+	if stream.Type != StreamDASH {
+		return false
+	}
+	return options.Profile == nil || options.Profile.Mode != TranscodeStream
+}
+
+func (b *dashBackend) Resolve(ctx context.Context, stream *Stream) (string, error) {
+	return stream.URL, nil
+}
+
+func (b *dashBackend) resumesByBytes() bool { return true }
+
+func (b *dashBackend) Download(ctx context.Context, job *transferJob) (transferResult, error) {
+	report := job.Report
+
+	// 진행이 멈추면 요청을 취소해 지금까지 받은 부분을 남김
+	reqCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := newMediaRequest(reqCtx, job.URL)
+	if err != nil {
+		return transferResult{}, err
+	}
+	if job.StartBytes > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", job.StartBytes))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return transferResult{}, fmt.Errorf("DASH 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 이미 끝까지 받은 파일
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && job.StartBytes > 0 {
+		return transferResult{}, remuxDASH(ctx, job.TargetFile, outputContainer(job.Options))
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && job.StartBytes > 0:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// 서버가 Range를 지원하지 않으면 처음부터 다시 받음
+		if job.StartBytes > 0 {
+			report.warn("서버가 이어받기를 지원하지 않아 처음부터 다시 받습니다.")
+			job.StartBytes = 0
+		}
+		flags |= os.O_TRUNC
+	default:
+		return transferResult{}, fmt.Errorf("DASH 요청 실패: %s", resp.Status)
+	}
+
+	// 전체 크기 (이어받는 경우 앞부분 포함)
+	total := job.StartBytes + resp.ContentLength
+	if resp.ContentLength < 0 {
+		total = 0
+	}
+
+	file, err := os.OpenFile(job.TargetFile, flags, 0644)
+	if err != nil {
+		return transferResult{}, err
+	}
+
+	report.report(ProgressEvent{
+		Type:       EventStarted,
+		Percent:    -1,
+		TotalBytes: total,
+		Duration:   job.Stream.Duration,
+		Message:    fmt.Sprintf("%s\nDASH URL: %s", startMessage("내장 DASH", job), job.URL),
+	})
+
+	monitor := startTransferMonitor(job, true)
+	go func() {
+		select {
+		case <-monitor.Stalled():
+			cancel()
+		case <-monitor.Done():
+		}
+	}()
+
+	// 받은 바이트 비율로 저장된 미디어 길이를 추정
+	counter := &progressWriter{w: file, written: job.StartBytes, onWrite: func(written int64) {
+		var outTime float64
+		if total > 0 {
+			outTime = float64(job.Stream.Duration) * float64(written) / float64(total)
+		}
+		monitor.update(outTime, written)
+	}}
+	_, copyErr := gatedCopy(reqCtx, counter, resp.Body, monitor.gate)
+	closeErr := file.Close()
+	result := monitor.stop()
+
+	if monitor.isStalled() {
+		return result, errStalled
+	}
+	if ctx.Err() != nil {
+		// .part는 바이트 단위로 이어받아야 하므로 그대로 두고, 받은 부분은 재생할 수 있는 파일로 따로 저장
+		playable, err := saveDASHPartial(ctx, job.TargetFile, outputContainer(job.Options))
+		if err != nil {
+			report.warn("받은 부분을 재생할 수 있는 파일로 저장하지 못했습니다: %v", err)
+		}
+		result.playable = playable
+		return result, nil
+	}
+	if copyErr != nil {
+		return result, fmt.Errorf("DASH 받기 오류: %v", copyErr)
+	}
+	if closeErr != nil {
+		return result, closeErr
+	}
+	if total > 0 && counter.written < total {
+		return result, fmt.Errorf("DASH 받기 오류: 파일이 끝까지 받아지지 않았습니다 (%s / %s)", formatBytes(counter.written), formatBytes(total))
+	}
+
+	// DASH 파일은 MP4이므로 다른 컨테이너로 저장하려면 리먹싱
+	if err := remuxDASH(ctx, job.TargetFile, outputContainer(job.Options)); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// progressWriter 기록한 바이트 수를 세어 알려주는 Writer
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	onWrite func(written int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.onWrite(p.written)
+	return n, err
+}
+
+// remuxDASH 받은 MP4 파일을 선택한 컨테이너로 리먹싱 (MP4면 그대로 사용)
+func remuxDASH(ctx context.Context, partFile string, container Container) error {
+	if container.orDefault() == ContainerMP4 {
+		return nil
+	}
+
+	name := strings.TrimSuffix(partFile, PartSuffix)
+	ext := filepath.Ext(name)
+	remuxFile := PartPath(strings.TrimSuffix(name, ext) + ".remux" + ext)
+	if err := remuxMP4(context.WithoutCancel(ctx), partFile, remuxFile, container); err != nil {
+		return err
+	}
+	return os.Rename(remuxFile, partFile)
+}
+
+// saveDASHPartial 중단된 DASH .part 파일에서 받은 부분을 재생할 수 있는 파일로 저장하는 함수
+// 서버의 MP4를 그대로 받은 .part는 moov가 파일 끝에 있으면 재생할 수 없으므로
+// moov가 앞쪽에 있을 때만 ffmpeg로 리먹싱해 완결된 파일을 만듦
+func saveDASHPartial(ctx context.Context, partFile string, container Container) (string, error) {
+	hasMoov, err := mp4HasMoov(partFile)
+	if err != nil {
+		return "", err
+	}
+	if !hasMoov {
+		return "", errors.New("받은 부분에 MP4 색인(moov)이 없어 끝까지 받아야 재생할 수 있습니다")
+	}
+
+	finalizeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ffmpegFinalizeTimeout)
+	defer cancel()
+	partial := PartialPath(strings.TrimSuffix(partFile, PartSuffix))
+	if err := remuxMP4(finalizeCtx, partFile, PartPath(partial), container); err != nil {
+		return "", err
+	}
+	if err := os.Rename(PartPath(partial), partial); err != nil {
+		return "", err
+	}
+	return partial, nil
+}
+
+// mp4HasMoov MP4 파일의 최상위 상자 중 moov가 온전히 들어 있는지 확인
+// 끝까지 받지 못한 파일은 마지막 상자(mdat)가 잘려 있을 수 있음
+func mp4HasMoov(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	var offset int64
+	header := make([]byte, 16)
+	for offset+8 <= info.Size() {
+		if _, err := file.ReadAt(header[:8], offset); err != nil {
+			return false, err
+		}
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		boxType := string(header[4:8])
+		switch size {
+		case 0:
+			// 파일 끝까지 이어지는 상자
+			size = info.Size() - offset
+		case 1:
+			// 64비트 크기
+			if _, err := file.ReadAt(header[8:16], offset+8); err != nil {
+				return false, nil
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+		}
+		if size < 8 {
+			return false, nil
+		}
+		if boxType == "moov" {
+			return offset+size <= info.Size(), nil
+		}
+		offset += size
+	}
+	return false, nil
+}
+
+// remuxMP4 input을 다시 인코딩하지 않고 container 형식의 output으로 저장
+func remuxMP4(ctx context.Context, input, output string, container Container) error {
+	args := []string{"-i", input, "-y", "-loglevel", "error", "-map", "0", "-c", "copy"}
+	args = append(args, container.FormatArgs()...)
+	cmd := exec.CommandContext(ctx, config.GetFFmpeg(), append(args, output)...)
+	detachFromConsoleSignals(cmd)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg 실행 실패: %v", err)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := cmd.Wait(); err != nil {
+		os.Remove(output)
+		return fmt.Errorf("%s 형식으로 리먹싱 실패: %v %s", container, err, strings.Join(lines, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/config"
+)
+
+// ffmpegBackend ffmpeg가 주소를 직접 입력으로 읽어 저장하는 백엔드 (HLS, DASH)
+// 입력을 ffmpeg가 직접 받으므로 여유 공간이 부족해도 일시 정지하지 않음
+type ffmpegBackend struct{}
+
+func (b *ffmpegBackend) Name() string { return "ffmpeg" }
+
+func (b *ffmpegBackend) Supports(stream *Stream, options *DownloadOptions) bool {
+	return stream.Type == StreamHLS || stream.Type == StreamDASH
+}
+
+// Resolve HLS는 선택한 화질의 재생목록, DASH는 화질별 파일 주소를 사용
+func (b *ffmpegBackend) Resolve(ctx context.Context, stream *Stream) (string, error) {
+	if stream.Type == StreamHLS {
+		return resolveHLSVariant(ctx, stream)
+	}
+	return stream.URL, nil
+}
+
+func (b *ffmpegBackend) Download(ctx context.Context, job *transferJob) (transferResult, error) {
+	options := job.Options
+	report := job.Report
+
+	outputArgs, err := ffmpegOutputArgs(options)
+	if err != nil {
+		return transferResult{}, err
+	}
+	if job.Stream.Type == StreamDASH && (options.Profile == nil || options.Profile.Mode != TranscodeStream) {
+		// DASH 입력은 MP4라 ADTS 변환이 필요 없음
+		outputArgs = append([]string{"-c", "copy"}, outputContainer(options).FormatArgs()...)
+	}
+
+	ffmpegArgs := []string{"-headers", mediaHeaders()}
+	if job.StartOffset > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-ss", strconv.FormatFloat(job.StartOffset, 'f', 3, 64))
+	}
+	ffmpegArgs = append(ffmpegArgs,
+		"-i", job.URL,
+		"-y",
+		"-nostats",
+		"-progress", "pipe:1", // 진행 상황을 key=value 블록으로 stdout에 출력
+		"-loglevel", "warning",
+	)
+	ffmpegArgs = append(ffmpegArgs, outputArgs...)
+	ffmpegCmd := exec.Command(config.GetFFmpeg(), append(ffmpegArgs, job.TargetFile)...)
+
+	// Ctrl+C는 이 프로세스만 받고, ffmpeg는 ctx 취소 시 q 입력으로 종료
+	detachFromConsoleSignals(ffmpegCmd)
+
+	ffmpegStdin, err := ffmpegCmd.StdinPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stdin pipe 생성 실패: %v", err)
+	}
+	ffmpegStdout, err := ffmpegCmd.StdoutPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stdout pipe 생성 실패: %v", err)
+	}
+	ffmpegStderr, err := ffmpegCmd.StderrPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stderr pipe 생성 실패: %v", err)
+	}
+
+	if err := ffmpegCmd.Start(); err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg 실행 실패: %v", err)
+	}
+
+	report.report(ProgressEvent{
+		Type:     EventStarted,
+		Percent:  -1,
+		OutTime:  job.StartOffset,
+		Duration: job.Stream.Duration,
+		Message:  fmt.Sprintf("%s\nffmpeg CMD: %s", startMessage("ffmpeg", job), ffmpegCmd.String()),
+	})
+
+	monitor := startTransferMonitor(job, false)
+
+	// 취소 요청 처리: q를 입력하면 ffmpeg가 받기를 멈추고 컨테이너를 마무리함
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			report.warn("중단 요청을 받았습니다. 지금까지 받은 부분을 저장하는 중...")
+		case <-monitor.Stalled():
+		case <-finished:
+			return
+		}
+
+		io.WriteString(ffmpegStdin, "q")
+		ffmpegStdin.Close()
+		select {
+		case <-time.After(ffmpegFinalizeTimeout):
+			report.warn("ffmpeg가 응답하지 않아 강제 종료합니다.")
+			ffmpegCmd.Process.Kill()
+		case <-finished:
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	// ffmpeg -progress 출력 파싱
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		readFFmpegProgress(ffmpegStdout, func(p ffmpegProgress) {
+			monitor.update(p.OutTime, p.TotalSize)
+		})
+	}()
+
+	// ffmpeg 로그 처리 (경고 이상만 출력됨)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(ffmpegStderr)
+		for scanner.Scan() {
+			report.warn("[FFMPEG] %s", scanner.Text())
+		}
+	}()
+
+	wg.Wait()
+	ffmpegErr := ffmpegCmd.Wait()
+	close(finished)
+	result := monitor.stop()
+
+	if monitor.isStalled() {
+		return result, errStalled
+	}
+	if ffmpegErr != nil && ctx.Err() == nil {
+		return result, fmt.Errorf("ffmpeg 실행 오류: %v", ffmpegErr)
+	}
+	return result, nil
+}
@@ -0,0 +1,353 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 이어받을 때 저장된 위치와 세그먼트 시작 위치의 차이를 같은 위치로 보는 범위 (초)
+const resumeSegmentTolerance = 0.5
+
+// hlsBackend 재생목록을 직접 읽어 세그먼트를 받아 ffmpeg로 저장하는 백엔드 (streamlink 불필요)
+type hlsBackend struct{}
+
+func (b *hlsBackend) Name() string { return "hls" }
+
+func (b *hlsBackend) Supports(stream *Stream, options *DownloadOptions) bool {
+	return stream.Type == StreamHLS
+}
+
+// Resolve 마스터 재생목록에서 선택한 화질의 재생목록 주소를 찾음
+func (b *hlsBackend) Resolve(ctx context.Context, stream *Stream) (string, error) {
+	return resolveHLSVariant(ctx, stream)
+}
+
+func (b *hlsBackend) Download(ctx context.Context, job *transferJob) (transferResult, error) {
+	source := &hlsSource{job: job}
+	return runFFmpegPipe(ctx, "내장 HLS+ffmpeg", source, job)
+}
+
+// hlsSegment 미디어 재생목록의 세그먼트
+type hlsSegment struct {
+	Sequence int64 // 미디어 시퀀스 번호
+	URL      string
+	Start    float64 // 영상 시작부터의 위치 (초)
+	Duration float64
+}
+
+// hlsPlaylist 미디어 재생목록
+type hlsPlaylist struct {
+	InitURL  string // EXT-X-MAP (fMP4 초기화 세그먼트)
+	Segments []hlsSegment
+}
+
+// hlsVariant 마스터 재생목록의 화질별 재생목록
+type hlsVariant struct {
+	URL    string
+	Height int
+}
+
+// fetchPlaylist 재생목록을 받아 줄 단위로 반환
+func fetchPlaylist(ctx context.Context, playlistURL string) ([]string, error) {
+	req, err := newMediaRequest(ctx, playlistURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("재생목록 요청 실패: %s", resp.Status)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || lines[0] != "#EXTM3U" {
+		return nil, errors.New("HLS 재생목록이 아닙니다")
+	}
+	return lines, nil
+}
+
+// resolveReference 재생목록 기준 상대 주소를 절대 주소로 변환
+// 상대 주소에 쿼리가 없으면 재생목록의 인증 쿼리를 이어받음
+func resolveReference(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	resolved := baseURL.ResolveReference(refURL)
+	if !refURL.IsAbs() && refURL.RawQuery == "" {
+		resolved.RawQuery = baseURL.RawQuery
+	}
+	return resolved.String(), nil
+}
+
+// attributeValue 태그 속성 목록에서 값을 찾음 (예: RESOLUTION=1920x1080)
+func attributeValue(attributes, key string) string {
+	for _, attr := range splitAttributes(attributes) {
+		name, value, ok := strings.Cut(attr, "=")
+		if ok && strings.EqualFold(name, key) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// splitAttributes 따옴표 안의 쉼표는 무시하고 속성 목록을 나눔
+func splitAttributes(attributes string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range attributes {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ',' && !quoted:
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, strings.TrimSpace(current.String()))
+	}
+	return parts
+}
+
+// resolveHLSVariant 마스터 재생목록에서 선택한 화질의 재생목록 주소를 찾는 함수
+// 주소가 이미 미디어 재생목록이면 그대로 사용
+func resolveHLSVariant(ctx context.Context, stream *Stream) (string, error) {
+	lines, err := fetchPlaylist(ctx, stream.URL)
+	if err != nil {
+		return "", err
+	}
+
+	var variants []hlsVariant
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#EXTINF:") {
+			return stream.URL, nil
+		}
+		if !strings.HasPrefix(line, "#EXT-X-STREAM-INF:") || i+1 >= len(lines) {
+			continue
+		}
+		variantURL, err := resolveReference(stream.URL, lines[i+1])
+		if err != nil {
+			return "", err
+		}
+		variant := hlsVariant{URL: variantURL}
+		resolution := attributeValue(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"), "RESOLUTION")
+		if _, height, ok := strings.Cut(resolution, "x"); ok {
+			variant.Height, _ = strconv.Atoi(height)
+		}
+		variants = append(variants, variant)
+	}
+	if len(variants) == 0 {
+		return "", errors.New("재생목록에 화질 정보가 없습니다")
+	}
+
+	// 해상도가 같은 재생목록, 없으면 주소에 화질 ID가 들어간 재생목록
+	height, _ := strconv.Atoi(stream.Quality.Height)
+	for _, v := range variants {
+		if height > 0 && v.Height == height {
+			return v.URL, nil
+		}
+	}
+	for _, v := range variants {
+		if stream.Quality.ID != "" && strings.Contains(v.URL, stream.Quality.ID) {
+			return v.URL, nil
+		}
+	}
+	return "", fmt.Errorf("재생목록에서 %s 화질을 찾을 수 없습니다", stream.Quality.Quality)
+}
+
+// fetchMediaPlaylist 미디어 재생목록을 받아 세그먼트 목록을 만드는 함수
+func fetchMediaPlaylist(ctx context.Context, playlistURL string) (*hlsPlaylist, error) {
+	lines, err := fetchPlaylist(ctx, playlistURL)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist := &hlsPlaylist{}
+	var position, segmentDuration float64
+	var sequence int64
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			sequence, _ = strconv.ParseInt(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"), 10, 64)
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			if method := attributeValue(strings.TrimPrefix(line, "#EXT-X-KEY:"), "METHOD"); method != "" && method != "NONE" {
+				return nil, fmt.Errorf("암호화된 재생목록(%s)은 내장 HLS 백엔드로 받을 수 없습니다", method)
+			}
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			initURL, err := resolveReference(playlistURL, attributeValue(strings.TrimPrefix(line, "#EXT-X-MAP:"), "URI"))
+			if err != nil {
+				return nil, err
+			}
+			playlist.InitURL = initURL
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value, _, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			segmentDuration, _ = strconv.ParseFloat(value, 64)
+		case strings.HasPrefix(line, "#"):
+			// 다른 태그는 사용하지 않음
+		default:
+			segmentURL, err := resolveReference(playlistURL, line)
+			if err != nil {
+				return nil, err
+			}
+			playlist.Segments = append(playlist.Segments, hlsSegment{
+				Sequence: sequence,
+				URL:      segmentURL,
+				Start:    position,
+				Duration: segmentDuration,
+			})
+			position += segmentDuration
+			segmentDuration = 0
+			sequence++
+		}
+	}
+	if len(playlist.Segments) == 0 {
+		return nil, errors.New("재생목록에 세그먼트가 없습니다")
+	}
+	return playlist, nil
+}
+
+// resumeSegments 저장된 위치(offset초)부터 이어받을 세그먼트를 고르는 함수
+// 앞 세그먼트를 다시 받으면 그만큼 겹치므로 저장된 위치 이후에 시작하는 첫 세그먼트부터 반환하며,
+// 저장된 위치는 마지막 프레임의 시각이라 세그먼트 끝보다 조금 앞이므로 resumeSegmentTolerance만큼의 차이는 허용
+// 일부만 저장된 세그먼트의 나머지는 빠진 구간으로, 첫 세그먼트 시작과 저장된 위치의 차이는 skip으로 반환
+func resumeSegments(segments []hlsSegment, offset float64) (rest []hlsSegment, gaps []SegmentGap, skip float64) {
+	if offset <= 0 {
+		return segments, nil, 0
+	}
+	for i, segment := range segments {
+		if segment.Start >= offset-resumeSegmentTolerance {
+			return segments[i:], gaps, segment.Start - offset
+		}
+		if end := segment.Start + segment.Duration; end-offset > resumeSegmentTolerance {
+			gaps = append(gaps, SegmentGap{
+				Sequence: segment.Sequence,
+				Offset:   offset,
+				Duration: end - offset,
+				Reason:   "중단될 때 일부만 저장된 세그먼트",
+			})
+		}
+	}
+	return nil, gaps, 0
+}
+
+// hlsSource 세그먼트를 순서대로 받아 넘겨주는 입력
+type hlsSource struct {
+	job    *transferJob
+	cancel context.CancelFunc
+	reader *io.PipeReader
+	done   chan struct{}
+	err    error
+	gaps   []SegmentGap // 받지 못해 건너뛴 세그먼트
+	skip   float64      // 이어받을 때 세그먼트 경계에 맞추느라 저장된 위치에서 옮긴 길이 (초, 조금 앞이면 음수)
+}
+
+func (s *hlsSource) Start() (io.Reader, error) {
+	// Stop은 가져오기만 멈추고, 호출한 쪽의 ctx 취소는 ffmpeg 마무리 후에 반영됨
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	reader, writer := io.Pipe()
+	s.reader = reader
+	go func() {
+		defer close(s.done)
+		s.gaps, s.err = s.fetch(ctx, writer)
+		writer.CloseWithError(s.err)
+	}()
+	return reader, nil
+}
+
+// fetch 재생목록의 세그먼트를 StartOffset 이후부터 순서대로 받아 w에 기록
+// SegmentWorkers개의 세그먼트를 미리 받아 두고 순서에 맞춰 기록함
+func (s *hlsSource) fetch(ctx context.Context, w io.Writer) ([]SegmentGap, error) {
+	playlist, err := fetchMediaPlaylist(ctx, s.job.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// fMP4(EXT-X-MAP)가 아니면 MPEG-TS 세그먼트
+	mpegTS := playlist.InitURL == ""
+
+	var segments []indexedSegment
+	if playlist.InitURL != "" {
+		segments = append(segments, indexedSegment{sequence: -1, url: playlist.InitURL})
+	}
+	// 이어받기: 저장된 위치 이후에 시작하는 세그먼트부터 받음
+	rest, gaps, skip := resumeSegments(playlist.Segments, s.job.StartOffset)
+	s.skip = skip
+	for _, gap := range gaps {
+		s.job.Report.warn("중단될 때 일부만 저장된 세그먼트 %s의 나머지는 빠진 구간으로 기록합니다", gap)
+	}
+	for _, segment := range rest {
+		segments = append(segments, indexedSegment{
+			sequence: segment.Sequence,
+			url:      segment.URL,
+			offset:   segment.Start,
+			duration: segment.Duration,
+			mpegTS:   mpegTS,
+		})
+	}
+
+	lost, err := fetchSegmentsOrdered(ctx, w, segments, s.job.Options.SegmentWorkers, s.job.Options.SkipLostSegments, s.job.Report)
+	return append(gaps, lost...), err
+}
+
+// Stop 요청을 취소하고, ffmpeg로 넘기는 쪽이 멈춰 있어도 기록이 끝나도록 파이프를 닫음
+func (s *hlsSource) Stop() {
+	s.cancel()
+	s.reader.CloseWithError(context.Canceled)
+}
+
+func (s *hlsSource) Wait() error {
+	<-s.done
+	var lost *SegmentLostError
+	if errors.As(s.err, &lost) {
+		return s.err
+	}
+	if s.err != nil && !errors.Is(s.err, context.Canceled) && !errors.Is(s.err, io.ErrClosedPipe) {
+		return fmt.Errorf("HLS 세그먼트 받기 오류: %v", s.err)
+	}
+	return nil
+}
+
+// Gaps 받지 못해 건너뛴 세그먼트 목록 (Wait 이후 호출)
+func (s *hlsSource) Gaps() []SegmentGap {
+	return s.gaps
+}
+
+// SkippedSeconds 이어받을 때 저장된 위치에서 첫 세그먼트까지 옮긴 길이 (Wait 이후 호출)
+func (s *hlsSource) SkippedSeconds() float64 {
+	return s.skip
+}
+
+func (s *hlsSource) String() string {
+	return "HLS 재생목록: " + s.job.URL
+}
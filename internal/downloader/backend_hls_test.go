@@ -0,0 +1,187 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testPlaylist 주어진 길이의 세그먼트로 된 미디어 재생목록 (시퀀스 번호는 100부터)
+func testPlaylist(durations ...float64) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-MEDIA-SEQUENCE:100\n#EXT-X-PLAYLIST-TYPE:VOD\n")
+	for i, d := range durations {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\nseg%d.ts\n", d, 100+i)
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+	return b.String()
+}
+
+// testSegment 시퀀스 번호를 담은 MPEG-TS 패킷 하나
+func testSegment(sequence int64) []byte {
+	packet := bytes.Repeat([]byte{0xff}, tsPacketSize)
+	packet[0] = tsSyncByte
+	copy(packet[1:], strconv.FormatInt(sequence, 10))
+	return packet
+}
+
+// newPlaylistServer 재생목록과 세그먼트를 주는 서버
+func newPlaylistServer(t *testing.T, playlist string) *httptest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/media.m3u8" {
+			io.WriteString(w, playlist)
+			return
+		}
+		var sequence int64
+		if _, err := fmt.Sscanf(r.URL.Path, "/seg%d.ts", &sequence); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(testSegment(sequence))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// streamlinkFirstSequence streamlink가 --hls-start-offset으로 고르는 첫 세그먼트
+// (streamlink의 duration_to_sequence와 같이 시작 위치가 offset 이상인 첫 세그먼트)
+func streamlinkFirstSequence(segments []hlsSegment, offset float64) int64 {
+	var position float64
+	for _, segment := range segments {
+		if position >= offset {
+			return segment.Sequence
+		}
+		position += segment.Duration
+	}
+	return segments[len(segments)-1].Sequence
+}
+
+func TestResumeSegments(t *testing.T) {
+	segments := []hlsSegment{
+		{Sequence: 100, Start: 0, Duration: 6},
+		{Sequence: 101, Start: 6, Duration: 6},
+		{Sequence: 102, Start: 12, Duration: 6},
+		{Sequence: 103, Start: 18, Duration: 6},
+	}
+
+	tests := []struct {
+		name   string
+		offset float64
+		first  int64   // 처음 받는 세그먼트 (-1이면 없음)
+		gap    float64 // 빠진 구간 길이 (0이면 없음)
+		skip   float64
+	}{
+		{name: "from the start", offset: 0, first: 100},
+		{name: "just before a boundary", offset: 11.967, first: 102, skip: 0.033},
+		{name: "exactly on a boundary", offset: 12, first: 102},
+		{name: "just after a boundary", offset: 12.2, first: 102, skip: -0.2},
+		{name: "partly saved segment", offset: 9, first: 102, gap: 3, skip: 3},
+		{name: "at the end", offset: 23.98, first: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, gaps, skip := resumeSegments(segments, tt.offset)
+			switch {
+			case tt.first < 0 && len(rest) != 0:
+				t.Errorf("rest = %v, want none", rest)
+			case tt.first >= 0 && (len(rest) == 0 || rest[0].Sequence != tt.first):
+				t.Errorf("rest = %v, want from %d", rest, tt.first)
+			}
+			if math.Abs(skip-tt.skip) > 1e-9 {
+				t.Errorf("skip = %v, want %v", skip, tt.skip)
+			}
+			if tt.gap == 0 {
+				if len(gaps) != 0 {
+					t.Errorf("gaps = %v, want none", gaps)
+				}
+				return
+			}
+			if len(gaps) != 1 || gaps[0].Offset != tt.offset || math.Abs(gaps[0].Duration-tt.gap) > 1e-9 {
+				t.Errorf("gaps = %v, want %.1fs from %v", gaps, tt.gap, tt.offset)
+			}
+		})
+	}
+}
+
+// TestResumeBackendsAgree 내장 HLS와 streamlink 백엔드가 같은 세그먼트부터 이어받는지 확인
+func TestResumeBackendsAgree(t *testing.T) {
+	// 길이가 소수인 세그먼트 (초 단위로 자르면 경계가 어긋남)
+	durations := []float64{2.002, 2.002, 2.002, 2.002, 2.002, 2.002, 1.5}
+	server := newPlaylistServer(t, testPlaylist(durations...))
+	playlist, err := fetchMediaPlaylist(context.Background(), server.URL+"/media.m3u8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, offset := range []float64{0.5, 3.99, 4.004, 4.3, 5.5, 9.9} {
+		t.Run(strconv.FormatFloat(offset, 'f', -1, 64), func(t *testing.T) {
+			options := &DownloadOptions{Quality: "1080p", SegmentWorkers: 2, Reporter: &recordingReporter{}}
+			job := func() *transferJob {
+				return &transferJob{
+					URL:         server.URL + "/media.m3u8",
+					Stream:      &Stream{Type: StreamHLS, URL: server.URL + "/media.m3u8"},
+					StartOffset: offset,
+					Options:     options,
+					Report:      newJobReporter(options, "video.mp4"),
+				}
+			}
+
+			// 내장 HLS: 실제로 받은 첫 세그먼트
+			hls := &hlsSource{job: job()}
+			var out bytes.Buffer
+			hlsGaps, err := hls.fetch(context.Background(), &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Len() == 0 {
+				t.Fatal("no segments fetched")
+			}
+			hlsFirst, _ := strconv.ParseInt(strings.TrimRight(string(out.Bytes()[1:tsPacketSize]), "\xff"), 10, 64)
+
+			// streamlink: 넘기는 --hls-start-offset으로 streamlink가 고르는 첫 세그먼트
+			streamlink := &streamlinkSource{job: job()}
+			args := streamlink.args()
+			startOffset := -1.0
+			for i, arg := range args {
+				if arg == "--hls-start-offset" {
+					startOffset, err = strconv.ParseFloat(args[i+1], 64)
+					if err != nil {
+						t.Fatalf("--hls-start-offset %q: %v", args[i+1], err)
+					}
+				}
+			}
+			if startOffset < 0 {
+				t.Fatalf("no --hls-start-offset in %v", args)
+			}
+			streamlinkFirst := streamlinkFirstSequence(playlist.Segments, startOffset)
+
+			if hlsFirst != streamlinkFirst {
+				t.Errorf("hls starts at #%d, streamlink at #%d (offset %v)", hlsFirst, streamlinkFirst, args)
+			}
+
+			// 처음 받는 세그먼트는 저장된 위치보다 앞서 시작하지 않음 (허용 범위 제외)
+			first := playlist.Segments[hlsFirst-100]
+			if first.Start < offset-resumeSegmentTolerance {
+				t.Errorf("segment #%d starts at %v, before saved position %v", hlsFirst, first.Start, offset)
+			}
+			if math.Abs(hls.SkippedSeconds()-(first.Start-offset)) > 1e-9 ||
+				math.Abs(streamlink.SkippedSeconds()-hls.SkippedSeconds()) > 1e-9 {
+				t.Errorf("skipped: hls %v, streamlink %v, want %v", hls.SkippedSeconds(), streamlink.SkippedSeconds(), first.Start-offset)
+			}
+			if len(hlsGaps) != len(streamlink.Gaps()) {
+				t.Errorf("gaps: hls %v, streamlink %v", hlsGaps, streamlink.Gaps())
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/utils"
+)
+
+// streamlink 로그 줄 형식 (예: "[stream.hls][error] Failed to fetch segment 1234: ...")
+var streamlinkLogRegex = regexp.MustCompile(`^\[([\w.-]+)\]\[(\w+)\] (.*)$`)
+
+// streamlink 로그에서 세그먼트 번호를 찾기 위한 정규식
+var segmentLogRegex = regexp.MustCompile(`[Ss]egment (\d+)`)
+
+// streamlink --stream-segment-threads 최대값
+const maxStreamlinkSegmentThreads = 10
+
+// 이어받을 위치나 빠진 세그먼트의 위치를 찾으려고 재생목록을 받을 때의 제한 시간
+const gapPlaylistTimeout = 15 * time.Second
+
+// streamlinkBackend streamlink 출력을 ffmpeg로 저장하는 백엔드 (HLS)
+type streamlinkBackend struct{}
+
+func (b *streamlinkBackend) Name() string { return "streamlink" }
+
+func (b *streamlinkBackend) Supports(stream *Stream, options *DownloadOptions) bool {
+	if stream.Type != StreamHLS {
+		return false
+	}
+	_, err := os.Stat(config.GetStreamlink())
+	return err == nil
+}
+
+// Resolve streamlink가 마스터 재생목록에서 화질을 고르므로 그대로 사용
+func (b *streamlinkBackend) Resolve(ctx context.Context, stream *Stream) (string, error) {
+	return stream.URL, nil
+}
+
+func (b *streamlinkBackend) Download(ctx context.Context, job *transferJob) (transferResult, error) {
+	source := &streamlinkSource{job: job}
+	return runFFmpegPipe(ctx, "streamlink+ffmpeg", source, job)
+}
+
+// streamlinkSource streamlink --stdout 출력을 넘겨주는 입력
+type streamlinkSource struct {
+	job    *transferJob
+	cmd    *exec.Cmd
+	stderr sync.WaitGroup
+	gaps   []SegmentGap      // 받지 못해 건너뛴 세그먼트
+	lost   *SegmentLostError // 세그먼트를 받지 못해 중단한 경우
+
+	skip float64 // 이어받을 때 세그먼트 경계에 맞추느라 저장된 위치에서 옮긴 길이 (초)
+
+	playlistOnce sync.Once
+	playlist     []hlsSegment         // 미디어 재생목록의 세그먼트 (받지 못하면 nil)
+	segments     map[int64]hlsSegment // 시퀀스 번호별 세그먼트 위치 (재생목록을 받지 못하면 nil)
+}
+
+func (s *streamlinkSource) Start() (io.Reader, error) {
+	s.cmd = exec.Command(config.GetStreamlink(), s.args()...)
+
+	// Ctrl+C는 이 프로세스만 받고, streamlink는 ctx 취소 시 Stop으로 종료
+	detachFromConsoleSignals(s.cmd)
+
+	stdout, err := s.cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("streamlink stdout pipe 생성 실패: %v", err)
+	}
+	stderr, err := s.cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("streamlink stderr pipe 생성 실패: %v", err)
+	}
+
+	if err := s.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("streamlink 실행 실패: %v", err)
+	}
+
+	s.stderr.Add(1)
+	go s.readLog(stderr)
+
+	return stdout, nil
+}
+
+// args streamlink 실행 인자
+// 이어받을 때는 내장 HLS 백엔드와 같은 세그먼트(저장된 위치 이후에 시작하는 첫 세그먼트)부터 받도록
+// 재생목록에서 그 세그먼트의 시작 위치를 찾아 --hls-start-offset으로 넘김
+// (streamlink는 시작 위치가 이 값 이상인 첫 세그먼트부터 받음)
+func (s *streamlinkSource) args() []string {
+	args := []string{s.job.URL, s.job.Options.Quality, "--stdout",
+		"--stream-segment-attempts", strconv.Itoa(segmentRetries + 1)}
+	if offset := s.job.StartOffset; offset > 0 {
+		if playlist := s.loadPlaylist(); playlist != nil {
+			rest, gaps, skip := resumeSegments(playlist, offset)
+			for _, gap := range gaps {
+				s.job.Report.warn("중단될 때 일부만 저장된 세그먼트 %s의 나머지는 빠진 구간으로 기록합니다", gap)
+			}
+			s.gaps = append(s.gaps, gaps...)
+			if len(rest) > 0 {
+				s.skip = skip
+				offset = rest[0].Start
+			}
+		} else {
+			s.job.Report.warn("재생목록을 받지 못해 세그먼트 경계를 확인하지 않고 %s부터 이어받습니다", utils.SecondsToHms(int(offset)))
+		}
+		args = append(args, "--hls-start-offset", formatStartOffset(offset))
+	}
+	if workers := s.job.Options.SegmentWorkers; workers > 1 {
+		// streamlink는 최대 10개까지 동시에 받음
+		args = append(args, "--stream-segment-threads", strconv.Itoa(min(workers, maxStreamlinkSegmentThreads)))
+	}
+	return args
+}
+
+// formatStartOffset --hls-start-offset 값 (초, 소수점 셋째 자리까지)
+// 재생목록 길이를 더하는 과정의 반올림 오차로 세그먼트 시작보다 커지지 않도록 1밀리초 앞으로 잡음
+func formatStartOffset(offset float64) string {
+	return strconv.FormatFloat(max(offset-0.001, 0), 'f', 3, 64)
+}
+
+// readLog streamlink stderr 처리 (에러나 경고만 이벤트로 전달)
+// streamlink는 끝내 받지 못한 세그먼트를 error 수준으로 기록하고 건너뛰므로
+// 옵션에 따라 빠진 구간으로 기록하거나 받기를 멈춤
+// 수준은 "[모듈][수준]" 머리말로 판단 (메시지 안의 "error" 같은 단어는 수준과 관계없음)
+func (s *streamlinkSource) readLog(stderr io.Reader) {
+	defer s.stderr.Done()
+	report := s.job.Report
+	attempts := make(map[int64]int)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		matches := streamlinkLogRegex.FindStringSubmatch(line)
+		if matches == nil {
+			// 머리말이 없는 줄은 streamlink가 끝내며 출력하는 "error: ..." 같은 오류만 표시
+			if strings.HasPrefix(line, "error:") {
+				report.warn("[STREAMLINK] %s", line)
+			}
+			continue
+		}
+		level, message := matches[2], matches[3]
+		if level != "error" && level != "warning" && level != "critical" {
+			continue
+		}
+
+		// 세그먼트 관련 경고는 재시도 이벤트로, 오류는 빠진 구간으로 처리
+		if segmentMatch := segmentLogRegex.FindStringSubmatch(message); len(segmentMatch) > 1 {
+			if segment, err := strconv.ParseInt(segmentMatch[1], 10, 64); err == nil {
+				if level != "warning" {
+					s.segmentLost(SegmentGap{Sequence: segment, Offset: -1, Reason: message})
+					continue
+				}
+				attempts[segment]++
+				report.report(ProgressEvent{
+					Type:    EventSegmentRetried,
+					Percent: -1,
+					Segment: segment,
+					Attempt: attempts[segment],
+					Message: line,
+				})
+				continue
+			}
+		}
+
+		report.warn("[STREAMLINK] %s", line)
+	}
+}
+
+// segmentLost 받지 못한 세그먼트 처리
+// streamlink 로그에는 시퀀스 번호만 있으므로 재생목록의 EXTINF 길이로 위치를 찾음
+func (s *streamlinkSource) segmentLost(gap SegmentGap) {
+	if segment, ok := s.lookupSegment(gap.Sequence); ok {
+		gap.Offset = segment.Start
+		gap.Duration = segment.Duration
+	}
+	if !s.job.Options.SkipLostSegments {
+		if s.lost == nil {
+			s.lost = &SegmentLostError{Gap: gap}
+			s.Stop()
+		}
+		return
+	}
+	s.job.Report.warn("세그먼트 %s을(를) 받지 못해 건너뜁니다: %s", gap, gap.Reason)
+	s.gaps = append(s.gaps, gap)
+}
+
+// loadPlaylist 선택한 화질의 미디어 재생목록 세그먼트 (한 번만 받으며, 받지 못하면 nil)
+func (s *streamlinkSource) loadPlaylist() []hlsSegment {
+	s.playlistOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), gapPlaylistTimeout)
+		defer cancel()
+		playlistURL, err := resolveHLSVariant(ctx, s.job.Stream)
+		if err != nil {
+			return
+		}
+		playlist, err := fetchMediaPlaylist(ctx, playlistURL)
+		if err != nil {
+			return
+		}
+		s.playlist = playlist.Segments
+		s.segments = make(map[int64]hlsSegment, len(playlist.Segments))
+		for _, segment := range playlist.Segments {
+			s.segments[segment.Sequence] = segment
+		}
+	})
+	return s.playlist
+}
+
+// lookupSegment 미디어 재생목록에서 시퀀스 번호의 세그먼트를 찾음
+// 재생목록을 받지 못하면 위치를 알 수 없는 채로 기록
+func (s *streamlinkSource) lookupSegment(sequence int64) (hlsSegment, bool) {
+	s.loadPlaylist()
+	segment, ok := s.segments[sequence]
+	return segment, ok
+}
+
+func (s *streamlinkSource) Stop() {
+	if s.cmd != nil && s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+}
+
+func (s *streamlinkSource) Wait() error {
+	s.stderr.Wait()
+	err := s.cmd.Wait()
+	if s.lost != nil {
+		return s.lost
+	}
+	if err != nil {
+		return fmt.Errorf("streamlink 실행 오류: %v", err)
+	}
+	return nil
+}
+
+// Gaps 받지 못해 건너뛴 세그먼트 목록 (Wait 이후 호출)
+func (s *streamlinkSource) Gaps() []SegmentGap {
+	return s.gaps
+}
+
+// SkippedSeconds 이어받을 때 저장된 위치에서 첫 세그먼트까지 옮긴 길이
+func (s *streamlinkSource) SkippedSeconds() float64 {
+	return s.skip
+}
+
+func (s *streamlinkSource) String() string {
+	return "streamlink CMD: " + s.cmd.String()
+}
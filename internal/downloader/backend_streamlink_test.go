@@ -0,0 +1,176 @@
+package downloader
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingReporter 받은 이벤트를 모아 두는 진행 표시
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []ProgressEvent
+}
+
+func (r *recordingReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingReporter) ofType(eventType ProgressEventType) []ProgressEvent {
+	var events []ProgressEvent
+	for _, e := range r.events {
+		if e.Type == eventType {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// newTestStreamlinkSource 재생목록 대신 segments로 위치를 찾는 streamlink 입력
+func newTestStreamlinkSource(options *DownloadOptions, segments map[int64]hlsSegment) (*streamlinkSource, *recordingReporter) {
+	recorder := &recordingReporter{}
+	options.Reporter = recorder
+	source := &streamlinkSource{job: &transferJob{
+		URL:     "https://example.com/master.m3u8",
+		Stream:  &Stream{Type: StreamHLS, URL: "https://example.com/master.m3u8"},
+		Options: options,
+		Report:  newJobReporter(options, "video.mp4"),
+	}}
+	source.playlistOnce.Do(func() {})
+	source.segments = segments
+	return source, recorder
+}
+
+func runReadLog(source *streamlinkSource, log string) {
+	source.stderr.Add(1)
+	source.readLog(strings.NewReader(log))
+}
+
+func TestStreamlinkReadLog(t *testing.T) {
+	segments := map[int64]hlsSegment{
+		1234: {Sequence: 1234, Start: 2468, Duration: 2},
+		1235: {Sequence: 1235, Start: 2470, Duration: 2},
+	}
+
+	tests := []struct {
+		name     string
+		lines    []string
+		skipLost bool
+		gaps     []SegmentGap // 기대하는 빠진 구간 (Reason 제외)
+		lost     int64        // 중단시킨 세그먼트 (0이면 중단 없음)
+		retries  []int64      // 재시도 이벤트의 세그먼트
+		warnings int
+	}{
+		{
+			name: "info lines are ignored",
+			lines: []string{
+				"[cli][info] Found matching plugin hls for URL https://example.com/master.m3u8",
+				"[cli][info] Available streams: 360p (worst), 720p, 1080p (best)",
+				"[cli][info] Opening stream: 1080p (hls)",
+				"[stream.hls][info] Stopping stream early after 2470",
+			},
+		},
+		{
+			name: "failed segment is a gap with playlist position",
+			lines: []string{
+				"[stream.hls][error] Failed to fetch segment 1234: Unable to open URL: https://example.com/1234.ts (404 Client Error: Not Found for url: https://example.com/1234.ts)",
+			},
+			skipLost: true,
+			gaps:     []SegmentGap{{Sequence: 1234, Offset: 2468, Duration: 2}},
+			warnings: 1, // 건너뛴다는 안내
+		},
+		{
+			name: "failed segment outside the playlist has unknown position",
+			lines: []string{
+				"[stream.hls][error] Failed to fetch segment 99: Unable to open URL: https://example.com/99.ts (Read timed out.)",
+			},
+			skipLost: true,
+			gaps:     []SegmentGap{{Sequence: 99, Offset: -1}},
+			warnings: 1,
+		},
+		{
+			name: "failed segment stops the download without skip",
+			lines: []string{
+				"[stream.hls][error] Failed to fetch segment 1235: Unable to open URL: https://example.com/1235.ts (503 Server Error: Service Unavailable for url: https://example.com/1235.ts)",
+				"[stream.hls][error] Failed to fetch segment 1234: Unable to open URL: https://example.com/1234.ts (404 Client Error: Not Found for url: https://example.com/1234.ts)",
+			},
+			lost: 1235,
+		},
+		{
+			name: "segment warning mentioning an error is a retry",
+			lines: []string{
+				"[stream.hls][warning] Failed to fetch segment 1234, retrying: Unable to open URL: https://example.com/1234.ts (500 Server Error: Internal Server Error for url: https://example.com/1234.ts)",
+				"[stream.hls][warning] Failed to fetch segment 1234, retrying: HTTPSConnectionPool(host='example.com', port=443): Read timed out. (read timeout=10.0) ConnectionError",
+			},
+			retries: []int64{1234, 1234},
+		},
+		{
+			name: "playlist reload warning is shown, not a gap",
+			lines: []string{
+				"[stream.hls][warning] Failed to reload playlist: Unable to open URL: https://example.com/chunklist.m3u8 (500 Server Error: Internal Server Error for url: https://example.com/chunklist.m3u8)",
+				"[stream.hls][warning] Encountered a stream discontinuity. This is unsupported and will result in incoherent output data.",
+			},
+			warnings: 2,
+		},
+		{
+			name: "cli errors are shown",
+			lines: []string{
+				"[cli][error] Try 1/1: Could not open stream <HLSStream ['hls', 'https://example.com/chunklist.m3u8']> (Could not open stream: Unable to open URL: https://example.com/chunklist.m3u8 (403 Client Error: Forbidden))",
+				"error: Could not open stream <HLSStream ['hls', 'https://example.com/chunklist.m3u8']>, tried 1 times, exiting",
+			},
+			warnings: 2,
+		},
+		{
+			name: "debug segment lines are ignored",
+			lines: []string{
+				"[stream.hls][debug] Adding segment 1234 to queue",
+				"[stream.hls][debug] Writing segment 1234 to output",
+				"[stream.hls][debug] Segment 1234 complete",
+				"[stream.segmented][debug] Closing worker thread",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, recorder := newTestStreamlinkSource(&DownloadOptions{SkipLostSegments: tt.skipLost}, segments)
+			runReadLog(source, strings.Join(tt.lines, "\n")+"\n")
+
+			if len(source.gaps) != len(tt.gaps) {
+				t.Fatalf("gaps = %v, want %v", source.gaps, tt.gaps)
+			}
+			for i, gap := range source.gaps {
+				want := tt.gaps[i]
+				if gap.Sequence != want.Sequence || gap.Offset != want.Offset || gap.Duration != want.Duration {
+					t.Errorf("gap %d = %+v, want %+v", i, gap, want)
+				}
+				if gap.Reason == "" {
+					t.Errorf("gap %d has no reason", i)
+				}
+			}
+
+			switch {
+			case tt.lost == 0 && source.lost != nil:
+				t.Errorf("unexpected stop: %v", source.lost)
+			case tt.lost != 0 && (source.lost == nil || source.lost.Gap.Sequence != tt.lost):
+				t.Errorf("lost = %v, want segment %d", source.lost, tt.lost)
+			}
+
+			retries := recorder.ofType(EventSegmentRetried)
+			if len(retries) != len(tt.retries) {
+				t.Fatalf("retries = %v, want segments %v", retries, tt.retries)
+			}
+			for i, event := range retries {
+				if event.Segment != tt.retries[i] || event.Attempt != i+1 {
+					t.Errorf("retry %d = segment %d attempt %d", i, event.Segment, event.Attempt)
+				}
+			}
+
+			if warnings := recorder.ofType(EventWarning); len(warnings) != tt.warnings {
+				t.Errorf("warnings = %d, want %d: %v", len(warnings), tt.warnings, warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/config"
+)
+
+// ffmpeg 기능 확인 명령 하나의 제한 시간
+const ffmpegProbeTimeout = 15 * time.Second
+
+// FFmpegCapabilities ffmpeg 빌드가 지원하는 기능 (ffmpeg 파일이 바뀌기 전까지 캐시 파일에 저장해 재사용)
+type FFmpegCapabilities struct {
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	Version   string    `json:"version"`
+	Progress  bool      `json:"progress"` // -progress 옵션 지원
+	Encoders  []string  `json:"encoders"`
+	Muxers    []string  `json:"muxers"`
+	Demuxers  []string  `json:"demuxers"`
+	BSFs      []string  `json:"bitstreamFilters"`
+	Protocols []string  `json:"protocols"` // 입력 프로토콜
+}
+
+// ffmpeg 기능 종류
+const (
+	capabilityEncoder  = "인코더"
+	capabilityMuxer    = "출력 형식"
+	capabilityDemuxer  = "입력 형식"
+	capabilityBSF      = "비트스트림 필터"
+	capabilityProtocol = "입력 프로토콜"
+	capabilityOption   = "옵션"
+)
+
+// ffmpegRequirement 작업에 필요한 ffmpeg 기능 하나
+type ffmpegRequirement struct {
+	Kind   string // capabilityEncoder 등
+	Name   string
+	Reason string // 이 기능이 필요한 이유 (오류 메시지에 표시)
+}
+
+func (r ffmpegRequirement) String() string {
+	return fmt.Sprintf("%s %s (%s)", r.Name, r.Kind, r.Reason)
+}
+
+// FFmpegCapabilityError ffmpeg에 작업에 필요한 기능이 없을 때의 오류
+type FFmpegCapabilityError struct {
+	Path    string
+	Version string
+	Missing []ffmpegRequirement
+}
+
+func (e *FFmpegCapabilityError) Error() string {
+	missing := make([]string, len(e.Missing))
+	for i, r := range e.Missing {
+		missing[i] = r.String()
+	}
+	return fmt.Sprintf("ffmpeg %s(%s)에 필요한 기능이 없습니다: %s. 이 기능이 포함된 ffmpeg 빌드로 바꾸세요 (chzzk-downloader deps update 또는 PATH의 ffmpeg 교체)",
+		e.Version, e.Path, strings.Join(missing, ", "))
+}
+
+// 같은 실행 중에는 한 번만 확인
+var (
+	capabilitiesMu     sync.Mutex
+	cachedCapabilities *FFmpegCapabilities
+)
+
+// ProbeFFmpeg 사용할 ffmpeg의 기능 확인
+// 파일 경로, 크기, 수정 시각이 같으면 메모리 또는 캐시 파일의 결과를 사용하고, 바뀌었으면 다시 확인
+func ProbeFFmpeg() (*FFmpegCapabilities, error) {
+	path := config.GetFFmpeg()
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg를 찾을 수 없습니다: %s", path)
+	}
+
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	if cachedCapabilities.matches(path, info) {
+		return cachedCapabilities, nil
+	}
+
+	cacheFile := filepath.Join(config.GetCacheDir(), config.FFmpegCapabilitiesFile)
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		var caps FFmpegCapabilities
+		if json.Unmarshal(data, &caps) == nil && caps.matches(path, info) {
+			cachedCapabilities = &caps
+			return cachedCapabilities, nil
+		}
+	}
+
+	caps, err := probeFFmpeg(path)
+	if err != nil {
+		return nil, err
+	}
+	caps.Size = info.Size()
+	caps.ModTime = info.ModTime()
+
+	// 캐시 저장 실패는 다음 실행에서 다시 확인하면 되므로 무시
+	if data, err := json.MarshalIndent(caps, "", "  "); err == nil && os.MkdirAll(filepath.Dir(cacheFile), 0755) == nil {
+		os.WriteFile(cacheFile, data, 0644)
+	}
+	cachedCapabilities = caps
+	return caps, nil
+}
+
+// matches 캐시한 결과가 지금의 ffmpeg 파일에 대한 것인지 확인
+func (c *FFmpegCapabilities) matches(path string, info os.FileInfo) bool {
+	return c != nil && c.Path == path && c.Size == info.Size() && c.ModTime.Equal(info.ModTime())
+}
+
+// probeFFmpeg ffmpeg에 버전, 인코더, 출력/입력 형식, 비트스트림 필터, 프로토콜 목록을 물어봄
+func probeFFmpeg(path string) (*FFmpegCapabilities, error) {
+	run := func(args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), ffmpegProbeTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, path, append([]string{"-hide_banner"}, args...)...).Output()
+		if err != nil {
+			return "", fmt.Errorf("ffmpeg 기능 확인 실패 (%s): %v", strings.Join(args, " "), err)
+		}
+		return string(out), nil
+	}
+
+	caps := &FFmpegCapabilities{Path: path}
+
+	out, err := run("-version")
+	if err != nil {
+		return nil, err
+	}
+	// ffmpeg version 7.1.1-essentials_build-www.gyan.dev Copyright ...
+	if fields := strings.Fields(out); len(fields) >= 3 && fields[1] == "version" {
+		caps.Version = fields[2]
+	}
+
+	if out, err = run("-h", "long"); err != nil {
+		return nil, err
+	}
+	caps.Progress = strings.Contains(out, "-progress")
+
+	if out, err = run("-encoders"); err != nil {
+		return nil, err
+	}
+	caps.Encoders = parseFFmpegTable(out)
+
+	if out, err = run("-muxers"); err != nil {
+		return nil, err
+	}
+	caps.Muxers = parseFFmpegTable(out)
+
+	if out, err = run("-demuxers"); err != nil {
+		return nil, err
+	}
+	caps.Demuxers = parseFFmpegTable(out)
+
+	if out, err = run("-bsfs"); err != nil {
+		return nil, err
+	}
+	caps.BSFs = parseFFmpegList(out, "Bitstream filters:")
+
+	if out, err = run("-protocols"); err != nil {
+		return nil, err
+	}
+	caps.Protocols = parseFFmpegProtocols(out)
+
+	return caps, nil
+}
+
+// parseFFmpegTable -encoders, -muxers, -demuxers 출력에서 이름 목록을 읽음
+// 설명 부분이 끝나는 구분선("--", "------") 뒤의 각 줄은 "플래그 이름[,이름...] 설명" 형식
+func parseFFmpegTable(out string) []string {
+	var names []string
+	started := false
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if !started {
+			started = len(fields) == 1 && strings.Trim(fields[0], "-") == ""
+			continue
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		names = append(names, strings.Split(fields[1], ",")...)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// parseFFmpegList 제목 줄 뒤에 이름이 한 줄에 하나씩 나오는 출력(-bsfs)을 읽음
+func parseFFmpegList(out, header string) []string {
+	var names []string
+	started := false
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !started {
+			started = line == header
+			continue
+		}
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseFFmpegProtocols -protocols 출력에서 입력(Input:) 프로토콜 목록을 읽음
+func parseFFmpegProtocols(out string) []string {
+	var names []string
+	input := false
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		switch line {
+		case "Input:":
+			input = true
+			continue
+		case "Output:":
+			input = false
+			continue
+		}
+		if input && line != "" {
+			names = append(names, line)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// has 기능이 있는지 확인
+func (c *FFmpegCapabilities) has(r ffmpegRequirement) bool {
+	switch r.Kind {
+	case capabilityEncoder:
+		return slices.Contains(c.Encoders, r.Name)
+	case capabilityMuxer:
+		return slices.Contains(c.Muxers, r.Name)
+	case capabilityDemuxer:
+		return slices.Contains(c.Demuxers, r.Name)
+	case capabilityBSF:
+		return slices.Contains(c.BSFs, r.Name)
+	case capabilityProtocol:
+		return slices.Contains(c.Protocols, r.Name)
+	case capabilityOption:
+		return r.Name != "-progress" || c.Progress
+	}
+	return true
+}
+
+// Require 필요한 기능이 모두 있는지 확인하고, 없으면 빠진 기능을 모두 담은 *FFmpegCapabilityError 반환
+func (c *FFmpegCapabilities) Require(requirements []ffmpegRequirement) error {
+	var missing []ffmpegRequirement
+	for _, r := range requirements {
+		if !c.has(r) && !slices.Contains(missing, r) {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &FFmpegCapabilityError{Path: c.Path, Version: c.Version, Missing: missing}
+}
+
+// baseRequirements 모든 다운로드에 필요한 기능 (진행 표시)
+func baseRequirements() []ffmpegRequirement {
+	return []ffmpegRequirement{{capabilityOption, "-progress", "진행 표시"}}
+}
+
+// containerRequirements 컨테이너로 저장할 때 필요한 기능
+// adts: HLS의 AAC(ADTS)를 그대로 복사해 MP4/MKV에 넣는 경우
+func containerRequirements(container Container, adts bool, reason string) []ffmpegRequirement {
+	reqs := []ffmpegRequirement{{capabilityMuxer, container.muxer(), reason}}
+	if adts && container != ContainerTS {
+		reqs = append(reqs, ffmpegRequirement{capabilityBSF, "aac_adtstoasc", reason})
+	}
+	return reqs
+}
+
+// profileRequirements 트랜스코딩 프로필에 필요한 인코더와 출력 형식
+func profileRequirements(profile *TranscodeProfile) []ffmpegRequirement {
+	reason := fmt.Sprintf("트랜스코딩 프로필 '%s'", profile.Name)
+	var reqs []ffmpegRequirement
+	if profile.VideoCodec != "" && profile.VideoCodec != "copy" {
+		reqs = append(reqs, ffmpegRequirement{capabilityEncoder, profile.VideoCodec, reason})
+	}
+	if !profile.copiesAudio() {
+		reqs = append(reqs, ffmpegRequirement{capabilityEncoder, profile.AudioCodec, reason})
+	}
+	return append(reqs, containerRequirements(profile.Container, profile.copiesAudio(), reason)...)
+}
+
+// jobRequirements 다운로드 옵션에 필요한 기능 (백엔드와 관계없이 필요한 것)
+func jobRequirements(options *DownloadOptions) []ffmpegRequirement {
+	reqs := baseRequirements()
+	container := outputContainer(options)
+	reqs = append(reqs, containerRequirements(container, true, fmt.Sprintf("출력 형식 %s", container))...)
+
+	if options.Profile != nil {
+		reqs = append(reqs, profileRequirements(options.Profile)...)
+	}
+	if options.ResumeOption != "" {
+		reqs = append(reqs, ffmpegRequirement{capabilityDemuxer, "concat", "이어받은 부분 합치기"})
+	}
+	if len(options.Chapters) > 0 {
+		reqs = append(reqs, ffmpegRequirement{capabilityDemuxer, "ffmetadata", "챕터 기록"})
+	}
+	if options.Split != nil {
+		reqs = append(reqs, ffmpegRequirement{capabilityMuxer, "segment", "파트 분할"})
+	}
+	return reqs
+}
+
+// backendRequirements 백엔드가 ffmpeg에 넘기는 입력에 필요한 기능
+func backendRequirements(name string, stream *Stream) []ffmpegRequirement {
+	reason := fmt.Sprintf("%s 백엔드", name)
+	switch name {
+	case "streamlink", "hls":
+		return []ffmpegRequirement{
+			{capabilityProtocol, "pipe", reason},
+			{capabilityDemuxer, "mpegts", reason},
+		}
+	case "ffmpeg":
+		reqs := []ffmpegRequirement{{capabilityProtocol, "https", reason}}
+		if stream.Type == StreamHLS {
+			return append(reqs, ffmpegRequirement{capabilityDemuxer, "hls", reason})
+		}
+		return append(reqs, ffmpegRequirement{capabilityDemuxer, "mov", reason})
+	case "dash":
+		return []ffmpegRequirement{{capabilityDemuxer, "mov", reason}}
+	}
+	return nil
+}
+
+// CheckBasics 모든 다운로드에 필요한 기능(진행 표시, 파이프 입력, MPEG-TS, 기본 출력 형식)이 있는지 확인
+func (c *FFmpegCapabilities) CheckBasics() error {
+	reqs := append(baseRequirements(), backendRequirements("hls", nil)...)
+	return c.Require(append(reqs, containerRequirements(DefaultContainer, true, "기본 출력 형식")...))
+}
+
+// CheckTranscodeProfile 프로필에 필요한 인코더와 출력 형식이 ffmpeg에 있는지 확인
+func CheckTranscodeProfile(profile *TranscodeProfile) error {
+	return checkFFmpegRequirements(profileRequirements(profile))
+}
+
+// checkFFmpegRequirements 작업 시작 전에 ffmpeg 기능 확인
+// 확인 자체가 실패하면(ffmpeg가 없는 경우 등) 실행할 때의 오류로 알 수 있으므로 통과시킴
+func checkFFmpegRequirements(requirements []ffmpegRequirement) error {
+	caps, err := ProbeFFmpeg()
+	if err != nil {
+		return nil
+	}
+	return caps.Require(requirements)
+}
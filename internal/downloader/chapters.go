@@ -0,0 +1,204 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/utils"
+)
+
+// ffmpeg 출력의 Duration 줄 (예: Duration: 01:23:45.67)
+var durationLineRegex = regexp.MustCompile(`Duration: (\d+):(\d{2}):(\d{2}(?:\.\d+)?)`)
+
+// 타임스탬프 목록의 한 줄 (예: "01:23:45 보스전", "1:23:45 - 보스전", "23:45 | 보스전")
+var timestampLineRegex = regexp.MustCompile(`^\s*((?:\d{1,2}:)?\d{1,2}:\d{2})\s*[-–|:.)]?\s*(.*)$`)
+
+// Chapter 챕터 정보
+type Chapter struct {
+	Start float64 `json:"start"` // 시작 위치 (초)
+	End   float64 `json:"end"`   // 끝 위치 (초, 0이면 다음 챕터 시작 또는 영상 끝)
+	Title string  `json:"title"`
+}
+
+// chapterJSON JSON 챕터 파일의 항목 ("time"에 시:분:초 또는 "start"에 초 단위로 지정)
+type chapterJSON struct {
+	Time  string   `json:"time"`
+	Start *float64 `json:"start"`
+	Title string   `json:"title"`
+}
+
+// LoadChapterFile 타임스탬프 목록 파일(텍스트 또는 .json)을 읽어 챕터 목록으로 변환하는 함수
+// 텍스트 파일은 한 줄에 "01:23:45 제목" 형식이며 타임스탬프가 없는 줄은 무시함
+func LoadChapterFile(path string) ([]Chapter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []Chapter
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var entries []chapterJSON
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("챕터 파일 파싱 실패: %v", err)
+		}
+		for i, entry := range entries {
+			var start float64
+			switch {
+			case entry.Start != nil:
+				start = *entry.Start
+			case timestampLineRegex.MatchString(entry.Time):
+				start = float64(utils.HmsToSeconds(entry.Time))
+			default:
+				return nil, fmt.Errorf("챕터 %d: 시작 위치(time 또는 start)가 올바르지 않습니다", i+1)
+			}
+			chapters = append(chapters, Chapter{Start: start, Title: entry.Title})
+		}
+	} else {
+		chapters = ParseTimestamps(string(data))
+	}
+
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("챕터 파일에서 타임스탬프를 찾지 못했습니다: %s", path)
+	}
+
+	SortChapters(chapters)
+	return chapters, nil
+}
+
+// ParseTimestamps "01:23:45 제목" 형식의 텍스트에서 챕터 목록을 추출하는 함수
+func ParseTimestamps(text string) []Chapter {
+	var chapters []Chapter
+	for _, line := range strings.Split(text, "\n") {
+		matches := timestampLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) < 3 {
+			continue
+		}
+
+		title := strings.TrimSpace(matches[2])
+		if title == "" {
+			title = matches[1]
+		}
+		chapters = append(chapters, Chapter{
+			Start: float64(utils.HmsToSeconds(matches[1])),
+			Title: title,
+		})
+	}
+	return chapters
+}
+
+// SortChapters 챕터를 시작 위치 순으로 정렬
+func SortChapters(chapters []Chapter) {
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+}
+
+// WriteChapters 파일에 챕터를 기록하는 함수 (재인코딩 없이 다시 저장)
+// TS는 챕터를 지원하지 않으므로 오류를 반환
+func WriteChapters(ctx context.Context, file string, chapters []Chapter, container Container) error {
+	if len(chapters) == 0 {
+		return nil
+	}
+	if container == ContainerTS {
+		return fmt.Errorf("TS 형식은 챕터를 지원하지 않습니다")
+	}
+	if container == "" {
+		container = DefaultContainer
+	}
+
+	total, err := probeDuration(file)
+	if err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(file)
+	base := strings.TrimSuffix(file, ext)
+	metadataFile := base + ".ffmeta"
+	tempFile := PartPath(base + ".chapters" + ext)
+
+	if err := writeFFMetadata(metadataFile, chapters, total); err != nil {
+		return fmt.Errorf("챕터 파일 작성 실패: %v", err)
+	}
+	defer os.Remove(metadataFile)
+
+	args := []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-i", file,
+		"-f", "ffmetadata", "-i", metadataFile,
+		"-map", "0",
+		"-map_metadata", "0",
+		"-map_chapters", "1",
+		"-c", "copy",
+		"-y",
+	}
+	args = append(args, container.FormatArgs()...)
+
+	cmd := exec.CommandContext(ctx, config.GetFFmpeg(), append(args, tempFile)...)
+	detachFromConsoleSignals(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("챕터 기록 실패: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return commitPartFile(tempFile, file)
+}
+
+// writeFFMetadata 챕터를 ffmpeg 메타데이터 파일(;FFMETADATA1) 형식으로 기록
+// 끝 위치가 없는 챕터는 다음 챕터 시작(마지막 챕터는 totalSeconds)으로 채움
+func writeFFMetadata(path string, chapters []Chapter, totalSeconds float64) error {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+
+	for i, chapter := range chapters {
+		end := chapter.End
+		if end <= chapter.Start {
+			if i+1 < len(chapters) {
+				end = chapters[i+1].Start
+			} else {
+				end = totalSeconds
+			}
+		}
+		if end <= chapter.Start {
+			continue
+		}
+
+		// 밀리초 단위로 기록
+		fmt.Fprintf(&b, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			int64(chapter.Start*1000), int64(end*1000), escapeFFMetadata(chapter.Title))
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// escapeFFMetadata 메타데이터 값의 특수문자(=, ;, #, \, 줄바꿈) 이스케이프
+func escapeFFMetadata(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", `\`+"\n")
+	return replacer.Replace(value)
+}
+
+// probeDuration ffmpeg로 파일의 재생 길이(초)를 확인하는 함수
+func probeDuration(path string) (float64, error) {
+	// 출력 파일 없이 실행하면 ffmpeg는 오류로 종료하지만 입력 정보는 stderr에 출력됨
+	output, _ := exec.Command(config.GetFFmpeg(), "-hide_banner", "-i", path).CombinedOutput()
+
+	matches := durationLineRegex.FindStringSubmatch(string(output))
+	if len(matches) < 4 {
+		return 0, fmt.Errorf("재생 길이를 확인할 수 없습니다: %s", path)
+	}
+
+	h, _ := strconv.Atoi(matches[1])
+	m, _ := strconv.Atoi(matches[2])
+	s, _ := strconv.ParseFloat(matches[3], 64)
+
+	return float64(h*3600+m*60) + s, nil
+}
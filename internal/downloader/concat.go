@@ -0,0 +1,96 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"chzzk-downloader/internal/config"
+)
+
+// ConcatFiles 여러 영상 파일을 재인코딩 없이 하나로 이어붙이는 함수 (ffmpeg concat demuxer)
+// container가 비어 있으면 출력 파일 확장자로 형식을 정함
+func ConcatFiles(ctx context.Context, inputs []string, outputFile string, container Container) error {
+	return ConcatWithChapters(ctx, inputs, outputFile, container, nil)
+}
+
+// ConcatWithChapters 여러 영상 파일을 이어붙이면서 챕터를 기록하는 함수
+// chapters가 비어 있으면 입력 파일의 챕터를 그대로 유지
+func ConcatWithChapters(ctx context.Context, inputs []string, outputFile string, container Container, chapters []Chapter) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("이어붙일 파일이 없습니다")
+	}
+
+	// concat 목록 파일 작성
+	listFile, err := os.CreateTemp(filepath.Dir(outputFile), ".concat-*.txt")
+	if err != nil {
+		return fmt.Errorf("concat 목록 파일 생성 실패: %v", err)
+	}
+	defer os.Remove(listFile.Name())
+
+	for _, input := range inputs {
+		absPath, err := filepath.Abs(input)
+		if err != nil {
+			listFile.Close()
+			return err
+		}
+		fmt.Fprintf(listFile, "file '%s'\n", escapeConcatPath(absPath))
+	}
+	if err := listFile.Close(); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listFile.Name(),
+	}
+
+	// 챕터 메타데이터 파일을 두 번째 입력으로 추가
+	if len(chapters) > 0 {
+		var total float64
+		for _, input := range inputs {
+			duration, err := probeDuration(input)
+			if err != nil {
+				return err
+			}
+			total += duration
+		}
+
+		metadataFile := listFile.Name() + ".ffmeta"
+		if err := writeFFMetadata(metadataFile, chapters, total); err != nil {
+			return fmt.Errorf("챕터 파일 작성 실패: %v", err)
+		}
+		defer os.Remove(metadataFile)
+
+		args = append(args, "-f", "ffmetadata", "-i", metadataFile, "-map", "0", "-map_chapters", "1")
+	}
+
+	args = append(args, "-c", "copy", "-y")
+	if container != "" {
+		// TS 입력을 MP4/MKV로 합칠 때는 AAC 변환 필요
+		if strings.EqualFold(filepath.Ext(inputs[0]), ".ts") {
+			args = append(args, container.MuxArgs()...)
+		} else {
+			args = append(args, container.FormatArgs()...)
+		}
+	}
+	cmd := exec.CommandContext(ctx, config.GetFFmpeg(), append(args, outputFile)...)
+	detachFromConsoleSignals(cmd)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg concat 실패: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// escapeConcatPath concat 목록에서 작은따옴표를 이스케이프
+func escapeConcatPath(path string) string {
+	return strings.ReplaceAll(filepath.ToSlash(path), "'", `'\''`)
+}
@@ -0,0 +1,113 @@
+package downloader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Container 출력 컨테이너 형식
+type Container string
+
+const (
+	ContainerMP4  Container = "mp4"  // MP4 (faststart: moov를 앞쪽에 배치)
+	ContainerFMP4 Container = "fmp4" // 조각난(fragmented) MP4, 중간에 끊겨도 재생 가능
+	ContainerMKV  Container = "mkv"  // Matroska
+	ContainerTS   Container = "ts"   // MPEG-TS 원본 그대로
+)
+
+// DefaultContainer 기본 출력 컨테이너
+const DefaultContainer = ContainerMP4
+
+// Containers 선택 가능한 컨테이너 목록
+var Containers = []Container{ContainerMP4, ContainerFMP4, ContainerMKV, ContainerTS}
+
+// ParseContainer 문자열을 컨테이너로 변환 (빈 문자열이면 기본값)
+func ParseContainer(name string) (Container, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultContainer, nil
+	}
+
+	for _, c := range Containers {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("알 수 없는 출력 형식: %s (mp4, fmp4, mkv, ts 중 선택)", name)
+}
+
+// orDefault 비어 있으면 기본 컨테이너 (옵션을 지정하지 않은 경우)
+func (c Container) orDefault() Container {
+	if c == "" {
+		return DefaultContainer
+	}
+	return c
+}
+
+// Extension 컨테이너의 파일 확장자
+func (c Container) Extension() string {
+	switch c {
+	case ContainerMKV:
+		return ".mkv"
+	case ContainerTS:
+		return ".ts"
+	default:
+		return ".mp4"
+	}
+}
+
+// Description 컨테이너 설명
+func (c Container) Description() string {
+	switch c {
+	case ContainerFMP4:
+		return "조각난 MP4 (중단되어도 재생 가능)"
+	case ContainerMKV:
+		return "MKV (Matroska)"
+	case ContainerTS:
+		return "TS (원본 스트림 그대로)"
+	default:
+		return "MP4 (faststart, 호환성 최고)"
+	}
+}
+
+// muxer ffmpeg 출력 포맷 이름
+func (c Container) muxer() string {
+	switch c {
+	case ContainerMKV:
+		return "matroska"
+	case ContainerTS:
+		return "mpegts"
+	default:
+		return "mp4"
+	}
+}
+
+// movflags MP4 계열 컨테이너의 movflags 값 (해당 없으면 빈 문자열)
+func (c Container) movflags() string {
+	switch c.orDefault() {
+	case ContainerMP4:
+		return "+faststart"
+	case ContainerFMP4:
+		return "+frag_keyframe+empty_moov+default_base_moof"
+	default:
+		return ""
+	}
+}
+
+// FormatArgs 출력 형식 관련 ffmpeg 인자 (-f, -movflags)
+func (c Container) FormatArgs() []string {
+	if flags := c.movflags(); flags != "" {
+		return []string{"-movflags", flags, "-f", c.muxer()}
+	}
+	return []string{"-f", c.muxer()}
+}
+
+// MuxArgs HLS(MPEG-TS) 입력을 이 컨테이너로 리먹싱할 때의 ffmpeg 인자
+func (c Container) MuxArgs() []string {
+	// HLS의 AAC는 ADTS 헤더를 가지므로 MP4/MKV에 넣을 때 변환 필요
+	if c == ContainerTS {
+		return c.FormatArgs()
+	}
+	return append([]string{"-bsf:a", "aac_adtstoasc"}, c.FormatArgs()...)
+}
@@ -1,46 +1,193 @@
 package downloader
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"chzzk-downloader/internal/api"
 )
 
 // DownloadVOD VOD 다운로드 함수
-func DownloadVOD(vodURL, quality, outputFolder, autoFilename, speedOption, downloadSection string) error {
-	// 다운로드 옵션 구성
-	options := &DownloadOptions{
-		VodURL:          vodURL,
-		Quality:         quality,
-		OutputFolder:    outputFolder,
-		Filename:        autoFilename,
-		SpeedOption:     speedOption,
-		DownloadSection: downloadSection,
-	}
+// ctx가 취소되면 저장된 부분까지 파일을 마무리하고 *InterruptedError를 반환
+// options는 바꾸지 않으므로 여러 다운로드에 같은 옵션을 다시 써도 됨
+func DownloadVOD(ctx context.Context, options *DownloadOptions) (*DownloadResult, error) {
+	// 이어받기 설정 등을 바꾸므로 호출한 쪽의 옵션은 그대로 두고 복사본을 사용
+	local := *options
+	options = &local
 
 	// 출력 경로 및 파일명 준비
 	outputFile, err := PrepareOutputPath(options)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// 중복 파일 처리
-	proceed, resumeOption := CheckDuplicateFile(outputFile)
-	if !proceed {
-		return nil
-	}
-	options.ResumeOption = resumeOption
+	report := newJobReporter(options, outputFile)
 
 	// VOD 정보 가져오기
-	_, _, err = api.GetVODQualities(vodURL)
+	qualities, vodInfo, err := api.GetVODQualities(options.VodURL)
+	if err != nil {
+		return nil, report.fail(err)
+	}
+
+	var selected api.Quality
+	for _, q := range qualities {
+		if q.ID == options.Quality {
+			selected = q
+			break
+		}
+	}
+	qualityName := selected.Quality
+	height, _ := strconv.Atoi(selected.Height)
+	bandwidth, _ := strconv.ParseInt(selected.Bandwidth, 10, 64)
+
+	// 다운로드 기록 확인: 같은 화질 이상으로 받은 파일이 남아 있으면 건너뛰고, 낮은 화질이면 다시 받음
+	// 덮어쓰기(-on-duplicate overwrite)는 기록과 관계없이 다시 받음
+	if !options.NoArchive && options.Duplicate != DuplicateOverwrite && vodInfo.VideoNo > 0 {
+		archive, err := LoadArchive()
+		if err != nil {
+			report.warn("다운로드 기록을 불러오지 못했습니다: %v", err)
+		} else if record := archive.Find(vodInfo.VideoNo); record != nil && record.Exists() {
+			if record.Height >= height {
+				report.warn("이미 %s 화질로 받은 영상이라 건너뜁니다: %s", record.Quality, record.Path)
+				return &DownloadResult{OutputFile: record.Path, Skipped: true, Archived: record}, nil
+			}
+			report.warn("이전에 받은 %s보다 높은 %s 화질로 다시 받습니다 (기존 파일: %s)", record.Quality, qualityName, record.Path)
+		}
+	}
+
+	// 중복 파일 처리
+	duplicate, err := resolveDuplicate(outputFile, options, report)
 	if err != nil {
-		return err
+		return nil, report.fail(err)
+	}
+	if duplicate.skip {
+		return &DownloadResult{OutputFile: outputFile, Skipped: true}, nil
+	}
+	if duplicate.resume {
+		options.ResumeOption = "--continue"
+	}
+	outputFile = duplicate.outputFile
+	report.outputFile = outputFile
+
+	// 이전 실행에서 남은 미완성 파일이 있으면 이어받기
+	detectStalePart(outputFile, options, report)
+
+	// 받기 전에 출력 형식, 변환, 분할, 챕터에 필요한 ffmpeg 기능 확인
+	if err := checkFFmpegRequirements(jobRequirements(options)); err != nil {
+		return nil, report.fail(err)
 	}
 
-	// HLS URL 가져오기
-	hlsURL, err := api.GetVODUrl(vodURL, "")
+	// 예상 크기(비트레이트 × 길이)만큼 여유 공간이 있는지 확인
+	if !options.SkipSpaceCheck {
+		estimate := EstimateOutputSize(bandwidth, vodInfo.Duration)
+		if err := checkDiskSpace(filepath.Dir(outputFile), PartPath(outputFile), estimate, options); err != nil {
+			return nil, report.fail(err)
+		}
+	}
+
+	// 스트림 주소와 받을 백엔드 결정
+	stream, err := ResolveStream(options.VodURL, vodInfo, selected)
 	if err != nil {
-		return err
+		return nil, report.fail(err)
+	}
+	backends, err := SelectBackends(options.Backend, stream, options)
+	if err != nil {
+		return nil, report.fail(err)
+	}
+
+	// 정보를 가져오는 동안 취소된 경우
+	if err := ctx.Err(); err != nil {
+		return nil, report.fail(err)
+	}
+
+	// 스트림 다운로드: 자동 선택이면 실패한 백엔드 대신 다음 백엔드로 다시 받음
+	// 받은 부분이 남아 있으면 다른 백엔드로 이어받을 수 없으므로 넘어가지 않음
+	var gaps []SegmentGap
+	for i, backend := range backends {
+		gaps, err = DownloadStream(ctx, backend, stream, outputFile, options)
+		if err == nil {
+			break
+		}
+		if i == len(backends)-1 || ctx.Err() != nil {
+			return nil, err
+		}
+		if _, statErr := os.Stat(PartPath(outputFile)); statErr == nil {
+			return nil, err
+		}
+		report.warn("%s 백엔드로 받지 못해 %s 백엔드로 다시 시도합니다: %v", backend.Name(), backends[i+1].Name(), err)
+	}
+
+	// 새로 받은 파일이 기존 파일보다 작으면 기존 파일을 그대로 사용
+	if duplicate.existingFile != "" {
+		kept, err := keepLarger(outputFile, duplicate.existingFile)
+		if err != nil {
+			return nil, report.fail(err)
+		}
+		if !kept {
+			report.warn("기존 파일이 더 커서 새로 받은 파일을 삭제했습니다: %s", duplicate.existingFile)
+			return &DownloadResult{OutputFile: duplicate.existingFile, Skipped: true}, nil
+		}
+		outputFile = duplicate.existingFile
+	}
+
+	result := &DownloadResult{
+		OutputFile: outputFile,
+		Duration:   vodInfo.Duration,
+		Gaps:       gaps,
+	}
+
+	// 받지 못해 건너뛴 세그먼트가 있으면 보고서 기록
+	if len(gaps) > 0 {
+		if err := WriteGapReport(outputFile, gaps); err != nil {
+			report.warn("빠진 구간 보고서를 기록하지 못했습니다: %v", err)
+		} else {
+			result.GapReport = GapReportPath(outputFile)
+		}
+	}
+
+	// 챕터 기록 (실패해도 다운로드한 파일은 그대로 사용 가능하므로 경고만 표시)
+	if len(options.Chapters) > 0 {
+		if err := WriteChapters(ctx, outputFile, options.Chapters, outputContainer(options)); err != nil {
+			report.warn("챕터를 기록하지 못했습니다: %v", err)
+		}
+	}
+
+	// 다운로드 후 변환하는 프로필이면 트랜스코딩 진행
+	finalFile := outputFile
+	finalContainer := outputContainer(options)
+	if options.Profile != nil && options.Profile.Mode == TranscodeAfter {
+		transcoded, err := TranscodeFile(ctx, outputFile, options.Profile, vodInfo.Duration, options)
+		if err != nil {
+			return result, fmt.Errorf("다운로드는 완료되었으나 트랜스코딩에 실패했습니다: %w", err)
+		}
+		result.TranscodedFile = transcoded
+		finalFile = transcoded
+		finalContainer = options.Profile.Container
+	}
+
+	// 파트 분할
+	if options.Split != nil {
+		manifest, err := SplitFile(ctx, finalFile, options.Split, vodInfo.Duration, finalContainer, options.Chapters)
+		if err != nil {
+			return result, fmt.Errorf("다운로드는 완료되었으나 파일 분할에 실패했습니다: %w", err)
+		}
+		result.Parts = manifest
+	}
+
+	// 다운로드 기록 저장 (실패해도 파일은 그대로 사용 가능하므로 경고만 표시)
+	if !options.NoArchive && vodInfo.VideoNo > 0 {
+		record, err := newArchiveRecord(result, options.VodURL, vodInfo.VideoNo, strings.TrimSpace(vodInfo.VideoTitle), vodInfo.Channel.ChannelName, qualityName, height)
+		if err == nil {
+			err = UpdateArchive(func(a *Archive) { a.Put(record) })
+		}
+		if err != nil {
+			report.warn("다운로드 기록을 저장하지 못했습니다: %v", err)
+		}
 	}
 
-	// HLS 스트림 다운로드 (streamlink + ffmpeg 사용)
-	return DownloadHLS(hlsURL, quality, outputFile)
+	return result, nil
 }
@@ -0,0 +1,174 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// 받는 중 여유 공간을 확인하는 주기
+const diskSpaceCheckInterval = 5 * time.Second
+
+// 예상 크기 여유분 (오디오 트랙과 컨테이너 오버헤드)
+const sizeEstimateMargin = 1.1
+
+// InsufficientSpaceError 예상 크기만큼 여유 공간이 없을 때의 오류
+type InsufficientSpaceError struct {
+	Folder    string
+	Required  int64
+	Available int64
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("저장 공간이 부족합니다 (필요: 약 %s, 남은 공간: %s, 폴더: %s)",
+		formatBytes(e.Required), formatBytes(e.Available), e.Folder)
+}
+
+// EstimateOutputSize 비트레이트(bps)와 영상 길이(초)로 예상 파일 크기를 계산 (알 수 없으면 0)
+func EstimateOutputSize(bandwidth int64, duration int) int64 {
+	if bandwidth <= 0 || duration <= 0 {
+		return 0
+	}
+	return int64(float64(bandwidth) / 8 * float64(duration) * sizeEstimateMargin)
+}
+
+// checkDiskSpace 다운로드 전에 출력 폴더의 여유 공간이 충분한지 확인하는 함수
+// 이어받기, 챕터 기록, 분할, 다운로드 후 변환은 원본과 새 파일이 잠시 함께 있으므로 두 배로 계산하고
+// 이미 받아 둔 .part 파일 크기는 뺌
+func checkDiskSpace(folder, partFile string, estimate int64, options *DownloadOptions) error {
+	if estimate <= 0 {
+		return nil
+	}
+
+	required := estimate
+	copies := options.ResumeOption == "--continue" ||
+		len(options.Chapters) > 0 ||
+		options.Split != nil ||
+		(options.Profile != nil && options.Profile.Mode == TranscodeAfter)
+	if copies {
+		required += estimate
+	}
+	if fileStat, err := os.Stat(partFile); err == nil {
+		required -= fileStat.Size()
+	}
+	required += options.MinFreeSpace
+
+	available, err := freeDiskSpace(folder)
+	if err != nil {
+		// 여유 공간을 알 수 없으면 확인하지 않고 진행
+		return nil
+	}
+	if available < required {
+		return &InsufficientSpaceError{Folder: folder, Required: required, Available: available}
+	}
+	return nil
+}
+
+// pauseGate 일시 정지 중이면 데이터 복사를 멈추는 장치
+// 복사가 멈추면 streamlink는 출력 대기로, ffmpeg는 입력 대기로 멈춤
+type pauseGate struct {
+	mu      sync.Mutex
+	resumed chan struct{} // 일시 정지 중이면 다시 시작할 때 닫히는 채널, 아니면 nil
+}
+
+func (g *pauseGate) pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resumed == nil {
+		g.resumed = make(chan struct{})
+	}
+}
+
+func (g *pauseGate) resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.resumed != nil {
+		close(g.resumed)
+		g.resumed = nil
+	}
+}
+
+func (g *pauseGate) paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.resumed != nil
+}
+
+// wait 일시 정지 중이면 다시 시작하거나 ctx가 취소될 때까지 대기
+func (g *pauseGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+	if resumed == nil {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// gatedCopy 일시 정지를 반영하며 src를 dst로 복사하는 함수
+func gatedCopy(ctx context.Context, dst io.Writer, src io.Reader, gate *pauseGate) (int64, error) {
+	buf := make([]byte, 256*1024)
+	var written int64
+	for {
+		if err := gate.wait(ctx); err != nil {
+			return written, err
+		}
+
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			w, err := dst.Write(buf[:n])
+			written += int64(w)
+			if err != nil {
+				return written, err
+			}
+		}
+		if readErr == io.EOF {
+			return written, nil
+		}
+		if readErr != nil {
+			return written, readErr
+		}
+	}
+}
+
+// watchDiskSpace 여유 공간이 minFree보다 줄어들면 일시 정지하고,
+// 다시 minFree의 1.5배 이상 확보되면 이어서 받는 감시 함수 (done이 닫히면 종료)
+func watchDiskSpace(folder string, minFree int64, gate *pauseGate, report *jobReporter, done <-chan struct{}) {
+	if minFree <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(diskSpaceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			available, err := freeDiskSpace(folder)
+			if err != nil {
+				continue
+			}
+
+			if !gate.paused() && available < minFree {
+				gate.pause()
+				report.warn("저장 공간이 부족해 일시 정지합니다 (남은 공간: %s). %s 이상 확보되면 이어서 받습니다.",
+					formatBytes(available), formatBytes(minFree*3/2))
+			} else if gate.paused() && available >= minFree*3/2 {
+				gate.resume()
+				report.warn("저장 공간이 확보되어 다시 받습니다 (남은 공간: %s).", formatBytes(available))
+			}
+		case <-done:
+			gate.resume()
+			return
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package downloader
+
+import "syscall"
+
+// freeDiskSpace 경로가 있는 디스크에서 현재 사용자가 쓸 수 있는 여유 공간 (바이트)
+func freeDiskSpace(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
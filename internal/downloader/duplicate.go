@@ -0,0 +1,191 @@
+package downloader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DuplicatePolicy 출력 파일이 이미 있을 때의 처리 방법
+type DuplicatePolicy string
+
+const (
+	DuplicateAsk        DuplicatePolicy = "ask"         // Prompter로 물어봄
+	DuplicateOverwrite  DuplicatePolicy = "overwrite"   // 기존 파일을 지우고 다시 받음
+	DuplicateSkip       DuplicatePolicy = "skip"        // 받지 않음
+	DuplicateResume     DuplicatePolicy = "resume"      // 이어받기 (이어받을 정보가 없으면 완성된 파일로 보고 건너뜀)
+	DuplicateRename     DuplicatePolicy = "rename"      // "파일명 (1).mp4"처럼 새 이름으로 받음
+	DuplicateKeepLarger DuplicatePolicy = "keep-larger" // 새 이름으로 받은 뒤 더 큰 파일만 남김
+)
+
+// DuplicatePolicies 선택 가능한 처리 방법 목록
+var DuplicatePolicies = []DuplicatePolicy{
+	DuplicateAsk,
+	DuplicateOverwrite,
+	DuplicateSkip,
+	DuplicateResume,
+	DuplicateRename,
+	DuplicateKeepLarger,
+}
+
+// ParseDuplicatePolicy 문자열을 중복 파일 처리 방법으로 변환 (빈 문자열은 ask)
+func ParseDuplicatePolicy(name string) (DuplicatePolicy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DuplicateAsk, nil
+	}
+	for _, p := range DuplicatePolicies {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+
+	names := make([]string, len(DuplicatePolicies))
+	for i, p := range DuplicatePolicies {
+		names[i] = string(p)
+	}
+	return "", fmt.Errorf("지원하지 않는 중복 파일 처리 방법입니다: %s (%s)", name, strings.Join(names, ", "))
+}
+
+// Description 처리 방법 설명
+func (p DuplicatePolicy) Description() string {
+	switch p {
+	case DuplicateAsk:
+		return "매번 묻기"
+	case DuplicateOverwrite:
+		return "중복파일 덮어쓰기"
+	case DuplicateSkip:
+		return "해당파일 건너뛰기"
+	case DuplicateResume:
+		return "해당파일 이어받기"
+	case DuplicateRename:
+		return "새 이름으로 저장"
+	case DuplicateKeepLarger:
+		return "새로 받아 더 큰 파일 유지"
+	default:
+		return string(p)
+	}
+}
+
+// DuplicatePrompter 출력 파일이 이미 있을 때 처리 방법을 묻는 인터페이스
+// DuplicateAsk 정책에서만 사용하며, 없으면 묻지 않고 건너뜀
+type DuplicatePrompter interface {
+	AskDuplicate(outputFile string) DuplicatePolicy
+}
+
+// duplicateAction 중복 파일 처리 결과
+type duplicateAction struct {
+	outputFile   string // 실제로 받을 파일 (rename, keep-larger이면 새 이름)
+	existingFile string // keep-larger에서 다운로드 후 비교할 기존 파일
+	resume       bool
+	skip         bool
+}
+
+// resolveDuplicate 출력 파일이 이미 있으면 정책에 따라 처리 방법을 정하는 함수
+func resolveDuplicate(outputFile string, options *DownloadOptions, report *jobReporter) (duplicateAction, error) {
+	action := duplicateAction{outputFile: outputFile}
+	if _, err := os.Stat(outputFile); err != nil {
+		return action, nil
+	}
+
+	policy := options.Duplicate
+	if policy == "" {
+		policy = DuplicateAsk
+	}
+	if policy == DuplicateAsk {
+		if options.Prompter == nil {
+			report.warn("파일 '%s'이(가) 이미 있어 건너뜁니다 (중복 파일 처리 방법 미지정)", outputFile)
+			action.skip = true
+			return action, nil
+		}
+		policy = options.Prompter.AskDuplicate(outputFile)
+	}
+
+	switch policy {
+	case DuplicateOverwrite:
+		if err := os.Remove(outputFile); err != nil {
+			return action, fmt.Errorf("파일 삭제 실패: %v", err)
+		}
+		RemoveResumeState(outputFile)
+	case DuplicateResume:
+		// 이어받기 정보가 없으면 이미 완성된 파일
+		state, _ := LoadResumeState(outputFile)
+		if state == nil {
+			report.warn("파일 '%s'은(는) 이미 완성된 파일이라 건너뜁니다", outputFile)
+			action.skip = true
+		} else {
+			// 이전 버전은 받는 중인 파일을 최종 이름으로 저장했으므로 .part로 옮겨 이어받음
+			if err := movePartForResume(outputFile, state, report); err != nil {
+				return action, fmt.Errorf("이어받을 파일 준비 실패: %v", err)
+			}
+			action.resume = true
+		}
+	case DuplicateRename:
+		action.outputFile = uniqueOutputPath(outputFile)
+	case DuplicateKeepLarger:
+		action.outputFile = uniqueOutputPath(outputFile)
+		action.existingFile = outputFile
+	default:
+		// DuplicateSkip 또는 알 수 없는 응답
+		action.skip = true
+	}
+
+	return action, nil
+}
+
+// movePartForResume 받던 파일을 .part로 옮기는 함수
+// .part가 이미 있으면 덮어쓰지 않고 더 큰 파일만 .part로 남기며,
+// 남은 파일이 이어받기 정보와 맞지 않으면 정보를 지워 파일에서 저장된 길이를 다시 확인하게 함
+func movePartForResume(outputFile string, state *ResumeState, report *jobReporter) error {
+	partFile := PartPath(outputFile)
+	if _, err := os.Stat(partFile); err != nil {
+		return os.Rename(outputFile, partFile)
+	}
+
+	kept, err := keepLarger(outputFile, partFile)
+	if err != nil {
+		return err
+	}
+	if kept {
+		report.warn("기존 미완성 파일(%s)보다 커서 '%s'을(를) 이어받습니다", filepath.Base(partFile), outputFile)
+	} else {
+		report.warn("미완성 파일(%s)이 더 커서 '%s'을(를) 삭제하고 미완성 파일을 이어받습니다", filepath.Base(partFile), outputFile)
+	}
+
+	if stat, err := os.Stat(partFile); err != nil || stat.Size() != state.SavedBytes {
+		RemoveResumeState(outputFile)
+	}
+	return nil
+}
+
+// uniqueOutputPath "파일명 (1).mp4"처럼 아직 없는 파일 경로를 찾는 함수
+func uniqueOutputPath(outputFile string) string {
+	ext := filepath.Ext(outputFile)
+	base := strings.TrimSuffix(outputFile, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// keepLarger 새로 받은 파일과 기존 파일 중 더 큰 파일을 기존 이름으로 남기는 함수
+// 새 파일이 남으면 true를 반환
+func keepLarger(newFile, existingFile string) (bool, error) {
+	newStat, err := os.Stat(newFile)
+	if err != nil {
+		return false, err
+	}
+	existingStat, err := os.Stat(existingFile)
+	if err != nil {
+		// 기존 파일이 그사이 없어졌으면 새 파일을 그 이름으로 옮김
+		return true, os.Rename(newFile, existingFile)
+	}
+
+	if newStat.Size() > existingStat.Size() {
+		return true, os.Rename(newFile, existingFile)
+	}
+	return false, os.Remove(newFile)
+}
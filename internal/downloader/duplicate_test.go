@@ -0,0 +1,62 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveDuplicateResumeKeepsExistingPart(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     int // 최종 이름으로 남은 파일 크기
+		part       int // 이미 있는 .part 크기
+		savedBytes int64
+		keepState  bool
+	}{
+		{name: "larger part is kept", output: 100, part: 300, savedBytes: 300, keepState: true},
+		{name: "larger output replaces part", output: 300, part: 100, savedBytes: 300, keepState: true},
+		{name: "state not matching kept file is dropped", output: 100, part: 300, savedBytes: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "video.mp4")
+			if err := os.WriteFile(outputFile, []byte(strings.Repeat("o", tt.output)), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(PartPath(outputFile), []byte(strings.Repeat("p", tt.part)), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := SaveResumeState(&ResumeState{OutputFile: outputFile, SavedSeconds: 10, SavedBytes: tt.savedBytes}); err != nil {
+				t.Fatal(err)
+			}
+
+			options := &DownloadOptions{Duplicate: DuplicateResume, Reporter: &recordingReporter{}}
+			action, err := resolveDuplicate(outputFile, options, newJobReporter(options, outputFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !action.resume {
+				t.Fatalf("action = %+v, want resume", action)
+			}
+
+			if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+				t.Errorf("%s still exists", outputFile)
+			}
+			stat, err := os.Stat(PartPath(outputFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := int64(max(tt.output, tt.part)); stat.Size() != want {
+				t.Errorf(".part size = %d, want larger file %d", stat.Size(), want)
+			}
+
+			state, _ := LoadResumeState(outputFile)
+			if (state != nil) != tt.keepState {
+				t.Errorf("resume state = %+v, want kept %v", state, tt.keepState)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package downloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// MPEG-TS 패킷 크기와 동기 바이트
+const (
+	tsPacketSize = 188
+	tsSyncByte   = 0x47
+)
+
+// SegmentGap 끝내 받지 못해 건너뛴 세그먼트 (출력 파일에서 이 구간이 빠짐)
+type SegmentGap struct {
+	Sequence int64   `json:"sequence"` // 미디어 시퀀스 번호
+	Offset   float64 `json:"offset"`   // 영상 시작부터의 위치 (초, 알 수 없으면 -1)
+	Duration float64 `json:"duration"` // 빠진 길이 (초, 알 수 없으면 0)
+	Reason   string  `json:"reason"`
+	VodURL   string  `json:"vodUrl,omitempty"` // 여러 VOD를 합친 파일에서 이 구간이 속한 VOD (시퀀스 번호는 VOD마다 따로 매김)
+}
+
+// String 구간 설명 (예: "#1234 01:02:03 (6.0초)")
+func (g SegmentGap) String() string {
+	if g.Offset < 0 {
+		return fmt.Sprintf("#%d 위치 알 수 없음", g.Sequence)
+	}
+	return fmt.Sprintf("#%d %s (%.1f초)", g.Sequence, utils.SecondsToHms(int(g.Offset)), g.Duration)
+}
+
+// SegmentLostError 세그먼트를 끝내 받지 못해 다운로드를 중단했을 때의 오류
+type SegmentLostError struct {
+	Gap SegmentGap
+}
+
+func (e *SegmentLostError) Error() string {
+	return fmt.Sprintf("세그먼트 %s을(를) 받지 못했습니다: %s (-skip-lost-segments로 건너뛰고 계속할 수 있음)", e.Gap, e.Gap.Reason)
+}
+
+// GapReport 빠진 구간 보고서 (<파일명>.gaps.json)
+type GapReport struct {
+	Source string       `json:"source"`
+	Gaps   []SegmentGap `json:"gaps"`
+}
+
+// GapReportPath 빠진 구간 보고서 파일 경로
+func GapReportPath(outputFile string) string {
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".gaps.json"
+}
+
+// WriteGapReport 빠진 구간을 보고서 파일로 기록하는 함수
+func WriteGapReport(outputFile string, gaps []SegmentGap) error {
+	data, err := json.MarshalIndent(GapReport{Source: filepath.Base(outputFile), Gaps: gaps}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(GapReportPath(outputFile), data, 0644)
+}
+
+// ReadGapReport 보고서 파일에 기록된 빠진 구간 (보고서가 없으면 nil)
+func ReadGapReport(outputFile string) ([]SegmentGap, error) {
+	data, err := os.ReadFile(GapReportPath(outputFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var report GapReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return report.Gaps, nil
+}
+
+// shiftGaps 빠진 구간을 합친 파일 기준 위치로 옮긴 복사본 (위치를 모르는 구간은 그대로)
+func shiftGaps(gaps []SegmentGap, offset float64, vodURL string) []SegmentGap {
+	shifted := make([]SegmentGap, 0, len(gaps))
+	for _, g := range gaps {
+		if g.Offset >= 0 {
+			g.Offset += offset
+		}
+		g.VodURL = vodURL
+		shifted = append(shifted, g)
+	}
+	return shifted
+}
+
+// mergeGaps 빠진 구간 목록을 시퀀스 번호 기준으로 합치는 함수 (같은 세그먼트는 한 번만)
+func mergeGaps(gaps ...[]SegmentGap) []SegmentGap {
+	bySequence := make(map[int64]SegmentGap)
+	for _, list := range gaps {
+		for _, g := range list {
+			bySequence[g.Sequence] = g
+		}
+	}
+	if len(bySequence) == 0 {
+		return nil
+	}
+
+	merged := make([]SegmentGap, 0, len(bySequence))
+	for _, g := range bySequence {
+		merged = append(merged, g)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Sequence < merged[j].Sequence })
+	return merged
+}
+
+// TotalGapDuration 빠진 구간의 길이 합계 (초)
+func TotalGapDuration(gaps []SegmentGap) float64 {
+	var total float64
+	for _, g := range gaps {
+		total += g.Duration
+	}
+	return total
+}
+
+// validateSegment 받은 세그먼트가 온전한지 확인하는 함수
+// MPEG-TS 세그먼트는 188바이트 패킷 단위이고 각 패킷이 동기 바이트로 시작해야 함
+func validateSegment(data []byte, expectedLength int64, mpegTS bool) error {
+	if len(data) == 0 {
+		return errors.New("빈 세그먼트")
+	}
+	if expectedLength > 0 && int64(len(data)) != expectedLength {
+		return fmt.Errorf("세그먼트 크기 불일치 (%d / %d 바이트)", len(data), expectedLength)
+	}
+	if !mpegTS {
+		return nil
+	}
+	if len(data)%tsPacketSize != 0 {
+		return fmt.Errorf("MPEG-TS 패킷 크기가 맞지 않습니다 (%d 바이트)", len(data))
+	}
+	for i := 0; i < len(data); i += tsPacketSize {
+		if data[i] != tsSyncByte {
+			return fmt.Errorf("MPEG-TS 동기 바이트 오류 (%d 바이트 위치)", i)
+		}
+	}
+	return nil
+}
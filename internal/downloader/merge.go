@@ -0,0 +1,353 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/api"
+)
+
+// MergeOptions 여러 VOD를 하나로 합치는 옵션
+type MergeOptions struct {
+	VodURLs      []string
+	Quality      string // 품질 이름 (예: 1080p), 비어 있으면 모든 VOD에 공통으로 있는 최고 품질
+	OutputFolder string
+	Filename     string // 비어 있으면 첫 VOD 정보로 생성
+	Container    Container
+	Chapters     []Chapter     // 합친 파일 기준 챕터 (VOD 경계 챕터와 함께 기록)
+	MinFreeSpace int64         // 받는 중 여유 공간이 이보다 줄어들면 일시 정지 (0이면 감시하지 않음)
+	StallTimeout time.Duration // 이 시간 동안 진행이 없으면 다시 받음 (0이면 감시하지 않음)
+	MaxRestarts  int           // 진행이 멈췄을 때 다시 받는 최대 횟수
+	Backend      string        // 다운로드 백엔드 이름 (비어 있으면 자동 선택)
+	Workers      int           // HLS 세그먼트를 동시에 받는 수
+	Jobs         int           // 동시에 받는 VOD 수 (1 이하면 하나씩)
+	SkipLost     bool          // 끝내 받지 못한 세그먼트를 건너뛰고 계속 받음
+	Reporter     ProgressReporter
+}
+
+// DefaultMergeJobs 합칠 VOD를 동시에 받는 수 기본값
+const DefaultMergeJobs = 2
+
+// MaxMergeJobs 합칠 VOD를 동시에 받는 수 최대값
+const MaxMergeJobs = 4
+
+// DetectConsecutiveVODs 같은 채널에서 같은 날 방송된 다시보기 VOD를 방송 순서대로 찾는 함수
+func DetectConsecutiveVODs(vodURL string) ([]string, error) {
+	_, vodInfo, err := api.GetVODQualities(vodURL)
+	if err != nil {
+		return nil, err
+	}
+	if vodInfo.Channel.ChannelID == "" {
+		return nil, fmt.Errorf("채널 정보를 찾을 수 없습니다")
+	}
+
+	day := liveDay(vodInfo.LiveOpenDate)
+	if day == "" {
+		return nil, fmt.Errorf("방송 날짜 정보가 없어 연속된 VOD를 찾을 수 없습니다")
+	}
+
+	videos, err := api.GetChannelVideos(vodInfo.Channel.ChannelID, 0, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	var sameDay []api.VideoSummary
+	for _, video := range videos {
+		openDate := video.LiveOpenDate
+		if openDate == "" {
+			openDate = video.PublishDate
+		}
+		if video.VideoType == "REPLAY" && liveDay(openDate) == day {
+			video.LiveOpenDate = openDate
+			sameDay = append(sameDay, video)
+		}
+	}
+
+	// 방송 시작 시각 순으로 정렬 ("YYYY-MM-DD HH:MM:SS" 형식이라 문자열 비교로 충분)
+	sort.Slice(sameDay, func(i, j int) bool {
+		return sameDay[i].LiveOpenDate < sameDay[j].LiveOpenDate
+	})
+
+	var urls []string
+	for _, video := range sameDay {
+		urls = append(urls, api.VideoURL(video.VideoNo))
+	}
+
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("같은 날 방송된 다시보기 VOD를 찾지 못했습니다")
+	}
+
+	return urls, nil
+}
+
+// liveDay 방송 시작 일시에서 날짜 부분만 추출
+func liveDay(liveOpenDate string) string {
+	day, _, _ := strings.Cut(strings.TrimSpace(liveOpenDate), " ")
+	return day
+}
+
+// SelectCommonQuality 모든 VOD에 공통으로 있는 품질 중 원하는 품질(없으면 최고 품질)을 고르는 함수
+func SelectCommonQuality(qualityLists [][]api.Quality, preferred string) (string, error) {
+	if len(qualityLists) == 0 {
+		return "", fmt.Errorf("품질 정보가 없습니다")
+	}
+
+	// 품질 이름별로 몇 개의 VOD에 있는지 세기
+	counts := make(map[string]int)
+	heights := make(map[string]int)
+	for _, qualities := range qualityLists {
+		seen := make(map[string]bool)
+		for _, q := range qualities {
+			if seen[q.Quality] {
+				continue
+			}
+			seen[q.Quality] = true
+			counts[q.Quality]++
+			if h, err := strconv.Atoi(q.Height); err == nil {
+				heights[q.Quality] = h
+			}
+		}
+	}
+
+	var common []string
+	for name, count := range counts {
+		if count == len(qualityLists) {
+			common = append(common, name)
+		}
+	}
+	if len(common) == 0 {
+		return "", fmt.Errorf("모든 VOD에 공통으로 있는 품질이 없습니다")
+	}
+
+	if preferred != "" {
+		for _, name := range common {
+			if name == preferred {
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("품질 '%s'이(가) 모든 VOD에 있지 않습니다 (공통 품질: %s)", preferred, strings.Join(common, ", "))
+	}
+
+	sort.Slice(common, func(i, j int) bool {
+		return heights[common[i]] > heights[common[j]]
+	})
+	return common[0], nil
+}
+
+// MergeVODs 여러 VOD를 같은 품질로 받아 재인코딩 없이 하나로 합치는 함수
+// 각 VOD의 경계에는 챕터를 기록함
+func MergeVODs(ctx context.Context, options *MergeOptions) (*DownloadResult, error) {
+	if len(options.VodURLs) < 2 {
+		return nil, fmt.Errorf("합칠 VOD가 2개 이상 필요합니다")
+	}
+
+	// 합치기와 챕터 기록에 필요한 ffmpeg 기능 확인
+	reqs := append(baseRequirements(),
+		ffmpegRequirement{capabilityDemuxer, "concat", "VOD 합치기"},
+		ffmpegRequirement{capabilityDemuxer, "ffmetadata", "VOD 경계 챕터"})
+	reqs = append(reqs, containerRequirements(outputContainer(&DownloadOptions{Container: options.Container}), false, "합친 파일 출력 형식")...)
+	if err := checkFFmpegRequirements(reqs); err != nil {
+		return nil, err
+	}
+
+	// 모든 VOD 정보와 품질 목록 확인
+	var infos []api.VodInfo
+	var qualityLists [][]api.Quality
+	for _, vodURL := range options.VodURLs {
+		qualities, vodInfo, err := api.GetVODQualities(vodURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", vodURL, err)
+		}
+		infos = append(infos, vodInfo)
+		qualityLists = append(qualityLists, qualities)
+	}
+
+	qualityName, err := SelectCommonQuality(qualityLists, options.Quality)
+	if err != nil {
+		return nil, err
+	}
+
+	// 최종 출력 경로
+	filename := options.Filename
+	if filename == "" {
+		first := infos[0]
+		filename = fmt.Sprintf("[%s] %s %s (병합)", liveDay(first.LiveOpenDate), first.Channel.ChannelName, strings.TrimSpace(first.VideoTitle))
+	}
+	outputFile, err := PrepareOutputPath(&DownloadOptions{
+		OutputFolder: options.OutputFolder,
+		Filename:     filename,
+		Container:    options.Container,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 각 VOD를 임시 폴더에 TS로 받음 (중단 후 다시 실행하면 이어받기 가능)
+	tempDir := filepath.Join(options.OutputFolder, ".merge-"+api.VideoNoFromURL(options.VodURLs[0]))
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return nil, err
+	}
+
+	// 각 VOD를 Jobs개씩 동시에 받음 (하나라도 실패하면 나머지도 멈춤)
+	results, err := downloadMergeParts(ctx, options, infos, qualityLists, qualityName, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	var chapters []Chapter
+	var gaps []SegmentGap
+	var offset float64
+	for i, result := range results {
+		partDuration, err := probeDuration(result.OutputFile)
+		if err != nil {
+			return nil, err
+		}
+
+		// 이전 실행에서 이미 받아 건너뛴 VOD는 남아 있는 보고서에서 빠진 구간을 읽음
+		partGaps := result.Gaps
+		if result.Skipped && len(partGaps) == 0 {
+			if partGaps, err = ReadGapReport(result.OutputFile); err != nil {
+				return nil, err
+			}
+		}
+		gaps = append(gaps, shiftGaps(partGaps, offset, options.VodURLs[i])...)
+
+		parts = append(parts, result.OutputFile)
+		chapters = append(chapters, Chapter{
+			Start: offset,
+			End:   offset + partDuration,
+			Title: fmt.Sprintf("%d. %s", i+1, strings.TrimSpace(infos[i].VideoTitle)),
+		})
+		offset += partDuration
+	}
+
+	// 사용자 챕터가 있으면 VOD 경계 챕터와 합쳐 시작 위치 순으로 기록
+	if len(options.Chapters) > 0 {
+		for i := range chapters {
+			chapters[i].End = 0
+		}
+		chapters = append(chapters, options.Chapters...)
+		SortChapters(chapters)
+	}
+
+	// 하나로 합치기
+	partFile := PartPath(outputFile)
+	if err := ConcatWithChapters(ctx, parts, partFile, options.Container, chapters); err != nil {
+		os.Remove(partFile)
+		return nil, err
+	}
+	if err := commitPartFile(partFile, outputFile); err != nil {
+		return nil, err
+	}
+
+	// 임시 파일 정리
+	os.RemoveAll(tempDir)
+
+	result := &DownloadResult{
+		OutputFile: outputFile,
+		Duration:   int(offset),
+		Gaps:       gaps,
+	}
+
+	// 각 VOD에서 빠진 구간을 합친 파일 기준으로 기록
+	if len(gaps) > 0 {
+		if err := WriteGapReport(outputFile, gaps); err != nil {
+			report := newJobReporter(&DownloadOptions{Reporter: options.Reporter}, outputFile)
+			report.warn("빠진 구간 보고서를 기록하지 못했습니다: %v", err)
+		} else {
+			result.GapReport = GapReportPath(outputFile)
+		}
+	}
+
+	return result, nil
+}
+
+// downloadMergeParts 합칠 VOD를 임시 폴더에 TS로 받아 VOD 순서대로 결과를 반환하는 함수
+// options.Jobs개까지 동시에 받으며, 하나가 실패하면 나머지를 취소하고 VOD 순서상 처음 실패한 오류를 반환
+func downloadMergeParts(parent context.Context, options *MergeOptions, infos []api.VodInfo, qualityLists [][]api.Quality, qualityName, tempDir string) ([]*DownloadResult, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	jobs := min(max(options.Jobs, 1), MaxMergeJobs)
+	slots := make(chan struct{}, jobs)
+	results := make([]*DownloadResult, len(options.VodURLs))
+	errs := make([]error, len(options.VodURLs))
+
+	var wg sync.WaitGroup
+	for i, vodURL := range options.VodURLs {
+		qualityID := ""
+		for _, q := range qualityLists[i] {
+			if q.Quality == qualityName {
+				qualityID = q.ID
+				break
+			}
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			title := strings.TrimSpace(infos[i].VideoTitle)
+			results[i], errs[i] = DownloadVOD(ctx, &DownloadOptions{
+				VodURL:           vodURL,
+				Quality:          qualityID,
+				OutputFolder:     tempDir,
+				Filename:         fmt.Sprintf("%02d_%s", i+1, api.VideoNoFromURL(vodURL)),
+				Container:        ContainerTS,
+				Duplicate:        DuplicateResume,
+				NoArchive:        true,
+				MinFreeSpace:     options.MinFreeSpace,
+				StallTimeout:     options.StallTimeout,
+				MaxRestarts:      options.MaxRestarts,
+				Backend:          options.Backend,
+				SegmentWorkers:   options.Workers,
+				SkipLostSegments: options.SkipLost,
+				JobID:            vodURL,
+				Title:            fmt.Sprintf("(%d/%d) %s", i+1, len(options.VodURLs), title),
+				Reporter:         options.Reporter,
+			})
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 다른 VOD가 실패해 취소된 VOD보다 실제로 실패한 VOD의 오류를 반환
+	// (사용자가 중단했으면 모두 중단 오류이므로 처음 것을 반환)
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if parent.Err() != nil || !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	if first != nil {
+		return nil, first
+	}
+	return results, nil
+}
@@ -0,0 +1,170 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 진행 상황을 확인하고 전달하는 주기
+const progressInterval = 500 * time.Millisecond
+
+// transferMonitor 백엔드 실행 중 진행 표시, 진행 멈춤 감지, 여유 공간 감시를 맡는 도우미
+type transferMonitor struct {
+	job       *transferJob
+	gate      *pauseGate
+	startedAt time.Time
+
+	mu          sync.Mutex
+	currentSize int64   // 이번 실행에서 저장된 파일 크기
+	outTime     float64 // 이번 실행에서 저장된 미디어 길이 (초)
+
+	stalled    chan struct{} // 진행이 멈춘 것을 감지하면 닫힘
+	stallOnce  sync.Once
+	done       chan struct{}
+	statusDone chan struct{}
+}
+
+// startTransferMonitor 감시를 시작하는 함수
+// pausable이 true면 여유 공간이 부족할 때 gate로 복사를 멈추고, false면 여유 공간을 감시하지 않음
+func startTransferMonitor(job *transferJob, pausable bool) *transferMonitor {
+	m := &transferMonitor{
+		job:        job,
+		gate:       &pauseGate{},
+		startedAt:  time.Now(),
+		stalled:    make(chan struct{}),
+		done:       make(chan struct{}),
+		statusDone: make(chan struct{}),
+	}
+
+	if pausable {
+		go watchDiskSpace(filepath.Dir(job.TargetFile), job.Options.MinFreeSpace, m.gate, job.Report, m.done)
+	}
+	go m.run()
+
+	return m
+}
+
+// update 백엔드가 알려준 진행 정보 반영 (크기는 커진 경우에만)
+func (m *transferMonitor) update(outTime float64, size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outTime = outTime
+	if size > m.currentSize {
+		m.currentSize = size
+	}
+}
+
+// Stalled 진행이 멈추면 닫히는 채널
+func (m *transferMonitor) Stalled() <-chan struct{} {
+	return m.stalled
+}
+
+// isStalled 진행이 멈춰 중단했는지 여부
+func (m *transferMonitor) isStalled() bool {
+	select {
+	case <-m.stalled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done 감시가 끝나면 닫히는 채널
+func (m *transferMonitor) Done() <-chan struct{} {
+	return m.done
+}
+
+// stop 감시를 끝내고 결과를 반환
+func (m *transferMonitor) stop() transferResult {
+	close(m.done)
+	<-m.statusDone
+
+	m.mu.Lock()
+	result := transferResult{
+		outTime: m.outTime,
+		size:    m.currentSize,
+		elapsed: time.Since(m.startedAt),
+	}
+	m.mu.Unlock()
+	if fileStat, err := os.Stat(m.job.TargetFile); err == nil {
+		result.size = fileStat.Size()
+	}
+	return result
+}
+
+// event 현재 상태로 진행 이벤트 생성
+func (m *transferMonitor) event(eventType ProgressEventType) ProgressEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.job
+	duration := job.Stream.Duration
+	elapsed := time.Since(m.startedAt)
+
+	// 진행률과 예상 크기는 이어받기 이전 부분까지 포함해서 계산
+	outTime := job.StartOffset + m.outTime
+	currentBytes := job.BaseBytes + m.currentSize
+	percent, totalBytes, _ := estimateProgress(outTime, duration, currentBytes, elapsed)
+
+	// 남은 시간은 이번 실행의 속도 기준으로 계산
+	_, _, eta := estimateProgress(m.outTime, duration-int(job.StartOffset), m.currentSize-job.StartBytes, elapsed)
+
+	return ProgressEvent{
+		Type:           eventType,
+		CurrentBytes:   currentBytes,
+		TotalBytes:     totalBytes,
+		Percent:        percent,
+		BytesPerSecond: float64(m.currentSize-job.StartBytes) / elapsed.Seconds(),
+		ETASeconds:     eta.Seconds(),
+		ElapsedSeconds: elapsed.Seconds(),
+		OutTime:        outTime,
+		Duration:       duration,
+	}
+}
+
+// run 파일 크기를 정기적으로 확인해 진행 이벤트를 전달하고 진행이 멈췄는지 감시
+func (m *transferMonitor) run() {
+	defer close(m.statusDone)
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	options := m.job.Options
+	report := m.job.Report
+
+	// 파일 크기와 미디어 시간이 StallTimeout 동안 그대로면 멈춘 것으로 판단
+	var lastSize int64
+	var lastOutTime float64
+	lastProgressAt := time.Now()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 출력 파일이 있는지 확인
+			fileStat, err := os.Stat(m.job.TargetFile)
+			m.mu.Lock()
+			if err == nil {
+				m.currentSize = fileStat.Size()
+			}
+			size, outTime := m.currentSize, m.outTime
+			m.mu.Unlock()
+
+			if size != lastSize || outTime != lastOutTime || m.gate.paused() {
+				// 여유 공간 부족으로 일시 정지한 동안은 멈춘 것으로 보지 않음
+				lastSize, lastOutTime = size, outTime
+				lastProgressAt = time.Now()
+			} else if options.StallTimeout > 0 && time.Since(lastProgressAt) >= options.StallTimeout {
+				m.stallOnce.Do(func() {
+					report.warn("%s 동안 진행이 없어 다시 연결합니다.", options.StallTimeout)
+					close(m.stalled)
+				})
+			}
+
+			// 500ms마다 진행 이벤트 전달
+			report.report(m.event(EventProgress))
+		case <-m.done:
+			return
+		}
+	}
+}
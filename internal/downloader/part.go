@@ -0,0 +1,118 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// 받는 중인 파일에 붙는 확장자 (완료되면 떼고 최종 이름으로 바꿈)
+const PartSuffix = ".part"
+
+// PartPath 출력 파일을 받는 동안 사용할 임시 파일 경로 (<파일명>.part)
+func PartPath(outputFile string) string {
+	return outputFile + PartSuffix
+}
+
+// PartialPath 중단된 다운로드에서 받은 부분만 재생할 수 있게 저장한 파일 경로 (<파일명>.partial.<확장자>)
+func PartialPath(outputFile string) string {
+	ext := filepath.Ext(outputFile)
+	return strings.TrimSuffix(outputFile, ext) + ".partial" + ext
+}
+
+// commitPartFile 임시 파일 내용을 디스크에 기록(fsync)한 뒤 최종 이름으로 바꾸는 함수
+// 다른 프로그램이 덜 받은 파일을 읽지 않도록 완성된 파일만 최종 이름으로 나타남
+func commitPartFile(partFile, outputFile string) error {
+	f, err := os.OpenFile(partFile, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(partFile, outputFile)
+}
+
+// detectStalePart 이전 실행에서 남은 미완성 파일(.part)이 있으면 이어받도록 설정하는 함수
+// 이어받기 정보가 없으면(강제 종료 등) 파일에서 저장된 길이를 확인해 새로 만들고,
+// 이어받을 수 없는 파일은 삭제해 처음부터 받음
+func detectStalePart(outputFile string, options *DownloadOptions, report *jobReporter) {
+	partFile := PartPath(outputFile)
+	fileStat, err := os.Stat(partFile)
+	if err != nil {
+		return
+	}
+
+	discard := func(reason string) {
+		report.warn("미완성 파일을 삭제하고 처음부터 받습니다 (%s): %s", reason, filepath.Base(partFile))
+		os.Remove(partFile)
+		RemoveResumeState(outputFile)
+	}
+
+	if options.Duplicate == DuplicateOverwrite {
+		discard("덮어쓰기")
+		return
+	}
+
+	state, err := LoadResumeState(outputFile)
+	if err != nil {
+		report.warn("이어받기 정보를 읽지 못했습니다: %v", err)
+	}
+
+	// 다른 영상이나 다른 화질로 받던 파일은 이어붙일 수 없음
+	if state != nil && (state.VodURL != options.VodURL || state.Quality != options.Quality) {
+		discard("다른 영상 또는 화질")
+		return
+	}
+
+	if state == nil || state.SavedSeconds <= 0 {
+		seconds, err := probeDuration(partFile)
+		if err != nil || seconds <= 0 {
+			discard("저장된 길이를 확인할 수 없음")
+			return
+		}
+
+		state = &ResumeState{
+			VodURL:       options.VodURL,
+			Quality:      options.Quality,
+			OutputFile:   outputFile,
+			SavedSeconds: seconds,
+			SavedBytes:   fileStat.Size(),
+		}
+		if err := SaveResumeState(state); err != nil {
+			report.warn("이어받기 정보 저장 실패: %v", err)
+			return
+		}
+	}
+
+	report.warn("미완성 파일(%s)이 있어 %s부터 이어받습니다.", filepath.Base(partFile), utils.SecondsToHms(int(state.SavedSeconds)))
+	options.ResumeOption = "--continue"
+}
+
+// FindStalePartFiles 폴더에서 이전 실행에서 남은 미완성 파일(.part) 목록을 찾는 함수
+// 이어받는 도중의 임시 파일(.cont, .joined, .remux)은 제외
+func FindStalePartFiles(folder string) []string {
+	matches, err := filepath.Glob(filepath.Join(folder, "*"+PartSuffix))
+	if err != nil {
+		return nil
+	}
+
+	var parts []string
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), PartSuffix)
+		ext := filepath.Ext(name)
+		if strings.HasSuffix(name, ".cont"+ext) || strings.HasSuffix(name, ".joined"+ext) || strings.HasSuffix(name, ".remux"+ext) ||
+			strings.HasSuffix(name, ".partial"+ext) {
+			continue
+		}
+		parts = append(parts, match)
+	}
+	return parts
+}
@@ -0,0 +1,197 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/utils"
+)
+
+// pipeSource ffmpeg 표준 입력으로 MPEG-TS를 넘겨주는 입력 (streamlink, 내장 HLS)
+type pipeSource interface {
+	// Start 입력을 시작하고 출력 스트림을 반환
+	Start() (io.Reader, error)
+	// Stop 가져오기를 멈춤 (출력 스트림은 EOF 또는 오류로 끝남)
+	Stop()
+	// Wait 입력이 끝날 때까지 대기하고 실패 원인을 반환
+	Wait() error
+	// String 시작 메시지에 표시할 설명
+	String() string
+}
+
+// ffmpegOutputArgs 입력을 출력 파일로 저장할 때의 ffmpeg 인자 (코덱, 컨테이너)
+func ffmpegOutputArgs(options *DownloadOptions) ([]string, error) {
+	if profile := options.Profile; profile != nil && profile.Mode == TranscodeStream {
+		// 받으면서 바로 변환
+		return append(profile.CodecArgs(), profile.MuxArgs()...), nil
+	}
+
+	container, err := ParseContainer(string(options.Container))
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{"-c", "copy"}, container.MuxArgs()...), nil
+}
+
+// startMessage 시작 이벤트에 표시할 다운로드 방식 설명
+func startMessage(name string, job *transferJob) string {
+	mode := "전체 다운로드"
+	if job.StartOffset > 0 {
+		mode = fmt.Sprintf("%s부터 이어받기", utils.SecondsToHms(int(job.StartOffset)))
+	} else if job.StartBytes > 0 {
+		mode = fmt.Sprintf("%s부터 이어받기", formatBytes(job.StartBytes))
+	}
+	return fmt.Sprintf("치지직 빠른 다시보기 => %s %s", name, mode)
+}
+
+// runFFmpegPipe source 출력을 ffmpeg로 job.TargetFile에 저장하는 파이프라인 실행
+// 취소되거나 진행이 멈추면 source를 먼저 멈춰 ffmpeg가 입력 끝(EOF)을 받고 컨테이너를 마무리하게 함
+func runFFmpegPipe(ctx context.Context, name string, source pipeSource, job *transferJob) (transferResult, error) {
+	options := job.Options
+	report := job.Report
+
+	// ffmpeg 명령어 준비 - 진행 정보는 stdout, 로그는 stderr로 분리
+	outputArgs, err := ffmpegOutputArgs(options)
+	if err != nil {
+		return transferResult{}, err
+	}
+	ffmpegArgs := []string{
+		"-i", "pipe:0",
+		"-y",
+		"-nostats",
+		"-progress", "pipe:1", // 진행 상황을 key=value 블록으로 stdout에 출력
+		"-loglevel", "warning",
+	}
+	ffmpegArgs = append(ffmpegArgs, outputArgs...)
+	ffmpegCmd := exec.Command(config.GetFFmpeg(), append(ffmpegArgs, job.TargetFile)...)
+
+	// Ctrl+C는 이 프로세스만 받고, ffmpeg는 ctx 취소 시 입력이 끝난 뒤 종료
+	detachFromConsoleSignals(ffmpegCmd)
+
+	ffmpegStdin, err := ffmpegCmd.StdinPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stdin pipe 생성 실패: %v", err)
+	}
+	ffmpegStdout, err := ffmpegCmd.StdoutPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stdout pipe 생성 실패: %v", err)
+	}
+	ffmpegStderr, err := ffmpegCmd.StderrPipe()
+	if err != nil {
+		return transferResult{}, fmt.Errorf("ffmpeg stderr pipe 생성 실패: %v", err)
+	}
+
+	// 명령어 실행
+	input, err := source.Start()
+	if err != nil {
+		return transferResult{}, err
+	}
+	if err := ffmpegCmd.Start(); err != nil {
+		source.Stop()
+		source.Wait()
+		return transferResult{}, fmt.Errorf("ffmpeg 실행 실패: %v", err)
+	}
+
+	report.report(ProgressEvent{
+		Type:     EventStarted,
+		Percent:  -1,
+		OutTime:  job.StartOffset,
+		Duration: job.Stream.Duration,
+		Message: fmt.Sprintf("%s\n%s\nffmpeg CMD: %s",
+			startMessage(name, job), source.String(), ffmpegCmd.String()),
+	})
+
+	// 여유 공간이 부족하면 source -> ffmpeg 복사를 멈춰 일시 정지
+	monitor := startTransferMonitor(job, true)
+
+	// 취소 요청 처리: source를 먼저 멈추면 ffmpeg는 입력 끝(EOF)을 받아 컨테이너를 정상적으로 마무리함
+	// 진행이 멈춘 경우도 같은 방법으로 지금까지 받은 부분을 마무리
+	var stopped bool
+	var stopMu sync.Mutex
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			report.warn("중단 요청을 받았습니다. 지금까지 받은 부분을 저장하는 중...")
+		case <-monitor.Stalled():
+		case <-finished:
+			return
+		}
+
+		stopMu.Lock()
+		stopped = true
+		stopMu.Unlock()
+		source.Stop()
+		select {
+		case <-time.After(ffmpegFinalizeTimeout):
+			report.warn("ffmpeg가 응답하지 않아 강제 종료합니다.")
+			ffmpegCmd.Process.Kill()
+		case <-finished:
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	// source -> ffmpeg stdin 복사
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ffmpegStdin.Close()
+		if _, err := gatedCopy(ctx, ffmpegStdin, input, monitor.gate); err != nil {
+			// ffmpeg가 먼저 종료된 경우 source가 출력 대기로 멈추지 않도록 종료
+			source.Stop()
+		}
+	}()
+
+	// ffmpeg -progress 출력 파싱
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		readFFmpegProgress(ffmpegStdout, func(p ffmpegProgress) {
+			monitor.update(p.OutTime, p.TotalSize)
+		})
+	}()
+
+	// ffmpeg 로그 처리 (경고 이상만 출력됨)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(ffmpegStderr)
+		for scanner.Scan() {
+			report.warn("[FFMPEG] %s", scanner.Text())
+		}
+	}()
+
+	// 파이프를 모두 읽은 뒤 명령어 종료 대기
+	wg.Wait()
+	sourceErr := source.Wait()
+	ffmpegErr := ffmpegCmd.Wait()
+	close(finished)
+	result := monitor.stop()
+	if tracker, ok := source.(gapTracker); ok {
+		result.gaps = tracker.Gaps()
+	}
+	if aligner, ok := source.(resumeAligner); ok {
+		result.outTime += aligner.SkippedSeconds()
+	}
+
+	if monitor.isStalled() {
+		return result, errStalled
+	}
+	if ffmpegErr != nil {
+		return result, fmt.Errorf("ffmpeg 실행 오류: %v", ffmpegErr)
+	}
+
+	stopMu.Lock()
+	defer stopMu.Unlock()
+	if sourceErr != nil && !stopped && ctx.Err() == nil {
+		return result, sourceErr
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package downloader
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachFromConsoleSignals 자식 프로세스를 별도 프로세스 그룹으로 실행
+// Ctrl+C가 자식에게 직접 전달되지 않게 하여 종료 순서를 직접 제어함
+func detachFromConsoleSignals(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,205 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// ProgressEventType 진행 이벤트 종류
+type ProgressEventType string
+
+const (
+	EventStarted        ProgressEventType = "started"
+	EventProgress       ProgressEventType = "progress"
+	EventSegmentRetried ProgressEventType = "segment_retried"
+	EventWarning        ProgressEventType = "warning"
+	EventCompleted      ProgressEventType = "completed"
+	EventFailed         ProgressEventType = "failed"
+)
+
+// ProgressEvent 다운로드 진행 이벤트
+type ProgressEvent struct {
+	Type       ProgressEventType `json:"type"`
+	JobID      string            `json:"jobId"`
+	Time       time.Time         `json:"time"`
+	Title      string            `json:"title,omitempty"`
+	OutputFile string            `json:"outputFile,omitempty"`
+
+	// 진행 정보 (started, progress, completed)
+	CurrentBytes   int64   `json:"currentBytes,omitempty"`
+	TotalBytes     int64   `json:"totalBytes,omitempty"` // 진행률로부터 추정한 전체 크기
+	Percent        float64 `json:"percent"`              // 알 수 없으면 -1
+	BytesPerSecond float64 `json:"bytesPerSecond,omitempty"`
+	ETASeconds     float64 `json:"etaSeconds,omitempty"`
+	ElapsedSeconds float64 `json:"elapsedSeconds,omitempty"`
+	OutTime        float64 `json:"outTime,omitempty"`  // 진행된 미디어 시간 (초)
+	Duration       int     `json:"duration,omitempty"` // 영상 전체 길이 (초)
+
+	// 세그먼트 재시도 정보 (segment_retried)
+	Segment int64 `json:"segment,omitempty"`
+	Attempt int   `json:"attempt,omitempty"`
+
+	// 경고/오류 메시지 (started, segment_retried, warning, failed)
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressReporter 다운로드 진행 상황을 전달받는 인터페이스
+// 여러 고루틴에서 동시에 호출될 수 있으므로 구현체는 동시성에 안전해야 함
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// NewProgressReporter 모드 이름으로 진행 상황 출력기를 생성 (line, multi, json)
+func NewProgressReporter(mode string, out io.Writer) (ProgressReporter, error) {
+	switch mode {
+	case "", "line":
+		return NewLineReporter(out), nil
+	case "multi":
+		return NewMultiBarReporter(out), nil
+	case "json":
+		return NewJSONReporter(out), nil
+	default:
+		return nil, fmt.Errorf("알 수 없는 진행 표시 방식: %s (line, multi, json 중 선택)", mode)
+	}
+}
+
+// LineReporter 한 줄을 계속 덮어쓰는 터미널 진행 표시
+type LineReporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLineReporter 한 줄 터미널 진행 표시 생성
+func NewLineReporter(out io.Writer) *LineReporter {
+	return &LineReporter{out: out}
+}
+
+// Report 이벤트를 터미널에 출력
+func (r *LineReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 이전 출력을 지울 공백 문자열
+	clearStr := strings.Repeat(" ", 100)
+
+	switch event.Type {
+	case EventStarted:
+		fmt.Fprintf(r.out, "\n[INFO] 다운로드 시작: %s\n", event.OutputFile)
+		if event.Message != "" {
+			fmt.Fprintln(r.out, event.Message)
+		}
+		fmt.Fprintln(r.out, "\n다운로드 진행 상황:")
+	case EventProgress:
+		fmt.Fprintf(r.out, "\r%s\r%s", clearStr, formatStatusLine(event))
+	case EventSegmentRetried:
+		fmt.Fprintf(r.out, "\r%s\r[재시도] 세그먼트 %d (%d회차): %s\n", clearStr, event.Segment, event.Attempt, event.Message)
+	case EventWarning:
+		fmt.Fprintf(r.out, "\r%s\r[경고] %s\n", clearStr, event.Message)
+	case EventCompleted:
+		fmt.Fprintf(r.out, "\r%s\r%s\n", clearStr, formatStatusLine(event))
+		fmt.Fprintf(r.out, "완료! (평균 속도: %s, 소요 시간: %s)\n",
+			formatBytes(int64(event.BytesPerSecond))+"/s", utils.SecondsToHms(int(event.ElapsedSeconds)))
+	case EventFailed:
+		fmt.Fprintf(r.out, "\r%s\r[실패] %s\n", clearStr, event.Message)
+	}
+}
+
+// formatStatusLine 진행 이벤트를 한 줄 상태 텍스트로 변환
+func formatStatusLine(event ProgressEvent) string {
+	var statusText string
+
+	// 바이트 크기 정보 (전체 크기는 진행률로부터 추정한 값)
+	if event.TotalBytes > 0 {
+		statusText = fmt.Sprintf("다운로드: %s / ~%s",
+			formatBytes(event.CurrentBytes),
+			formatBytes(event.TotalBytes))
+	} else {
+		statusText = fmt.Sprintf("다운로드: %s", formatBytes(event.CurrentBytes))
+	}
+
+	// 진행률 정보 추가
+	if event.Percent >= 0 {
+		statusText += fmt.Sprintf(" (%.1f%%)", event.Percent)
+	}
+
+	// 속도 정보 추가
+	if event.BytesPerSecond > 0 {
+		statusText += fmt.Sprintf(" | 속도: %s/s", formatBytes(int64(event.BytesPerSecond)))
+	}
+
+	// 남은 시간 정보 추가 (진행 중일 때만)
+	if event.Type == EventProgress && event.Percent >= 0 {
+		statusText += fmt.Sprintf(" | 남은 시간: %s", utils.SecondsToHms(int(event.ETASeconds)))
+	}
+
+	// 동영상 시간 정보 추가
+	if event.Duration > 0 {
+		statusText += fmt.Sprintf(" | 진행: %s / %s", utils.SecondsToHms(int(event.OutTime)), utils.SecondsToHms(event.Duration))
+	} else {
+		statusText += fmt.Sprintf(" | 진행: %s", utils.SecondsToHms(int(event.OutTime)))
+	}
+
+	return statusText
+}
+
+// jobReporter 작업 정보(ID, 제목, 출력 파일)를 채워 이벤트를 전달하는 도우미
+type jobReporter struct {
+	reporter   ProgressReporter
+	jobID      string
+	title      string
+	outputFile string
+}
+
+// newJobReporter 다운로드 옵션으로 작업용 이벤트 전달 도우미 생성
+func newJobReporter(options *DownloadOptions, outputFile string) *jobReporter {
+	reporter := options.Reporter
+	if reporter == nil {
+		reporter = NewLineReporter(os.Stdout)
+	}
+
+	jobID := options.JobID
+	if jobID == "" {
+		jobID = filepath.Base(outputFile)
+	}
+
+	return &jobReporter{
+		reporter:   reporter,
+		jobID:      jobID,
+		title:      options.Title,
+		outputFile: outputFile,
+	}
+}
+
+// report 작업 정보를 채워 이벤트 전달
+func (j *jobReporter) report(event ProgressEvent) {
+	event.JobID = j.jobID
+	event.Title = j.title
+	event.OutputFile = j.outputFile
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	j.reporter.Report(event)
+}
+
+// warn 경고 이벤트 전달
+func (j *jobReporter) warn(format string, args ...interface{}) {
+	j.report(ProgressEvent{Type: EventWarning, Percent: -1, Message: fmt.Sprintf(format, args...)})
+}
+
+// fail 실패 이벤트를 전달하고 오류를 그대로 반환
+func (j *jobReporter) fail(err error) error {
+	j.report(ProgressEvent{Type: EventFailed, Percent: -1, Message: err.Error()})
+	return err
+}
+
+// failf 오류를 생성해 실패 이벤트를 전달하고 반환
+func (j *jobReporter) failf(format string, args ...interface{}) error {
+	return j.fail(fmt.Errorf(format, args...))
+}
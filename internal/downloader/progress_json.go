@@ -0,0 +1,27 @@
+package downloader
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONReporter 이벤트를 한 줄에 하나씩 JSON으로 출력하는 진행 표시 (GUI 래퍼용)
+type JSONReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONReporter JSON Lines 진행 표시 생성
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{enc: json.NewEncoder(out)}
+}
+
+// Report 이벤트를 JSON 한 줄로 출력
+func (r *JSONReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 출력 실패는 다운로드에 영향을 주지 않도록 무시
+	_ = r.enc.Encode(event)
+}
@@ -0,0 +1,125 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// 진행 막대 너비
+const multiBarWidth = 30
+
+// multiBarJob 진행 막대 하나의 상태
+type multiBarJob struct {
+	label string
+	last  ProgressEvent
+}
+
+// MultiBarReporter 병렬 작업마다 진행 막대를 한 줄씩 표시하는 터미널 진행 표시
+type MultiBarReporter struct {
+	mu    sync.Mutex
+	out   io.Writer
+	jobs  []*multiBarJob
+	index map[string]*multiBarJob
+	drawn int // 마지막으로 그린 줄 수
+}
+
+// NewMultiBarReporter 여러 줄 터미널 진행 표시 생성
+func NewMultiBarReporter(out io.Writer) *MultiBarReporter {
+	return &MultiBarReporter{
+		out:   out,
+		index: make(map[string]*multiBarJob),
+	}
+}
+
+// Report 이벤트를 반영하고 진행 막대를 다시 그림
+func (r *MultiBarReporter) Report(event ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.index[event.JobID]
+	if !ok {
+		label := event.Title
+		if label == "" {
+			label = event.JobID
+		}
+		job = &multiBarJob{label: label}
+		r.index[event.JobID] = job
+		r.jobs = append(r.jobs, job)
+	}
+
+	switch event.Type {
+	case EventSegmentRetried, EventWarning:
+		// 메시지는 막대 위에 한 줄로 남기고 막대를 다시 그림
+		r.clear()
+		fmt.Fprintf(r.out, "[%s] %s\n", job.label, event.Message)
+	default:
+		// 실패 이벤트에는 진행 정보가 없으므로 마지막 진행 정보를 유지
+		if event.Type == EventFailed {
+			event.CurrentBytes = job.last.CurrentBytes
+			event.Percent = job.last.Percent
+		}
+		job.last = event
+		r.clear()
+	}
+
+	r.draw()
+}
+
+// clear 이전에 그린 막대를 지움
+func (r *MultiBarReporter) clear() {
+	if r.drawn == 0 {
+		return
+	}
+	// 커서를 그린 줄 수만큼 위로 올리고 아래를 모두 지움
+	fmt.Fprintf(r.out, "\x1b[%dA\x1b[J", r.drawn)
+	r.drawn = 0
+}
+
+// draw 모든 작업의 진행 막대를 그림
+func (r *MultiBarReporter) draw() {
+	for _, job := range r.jobs {
+		fmt.Fprintln(r.out, formatBar(job))
+	}
+	r.drawn = len(r.jobs)
+}
+
+// formatBar 작업 하나의 진행 막대 텍스트 생성
+func formatBar(job *multiBarJob) string {
+	event := job.last
+
+	label := job.label
+	if runes := []rune(label); len(runes) > 24 {
+		label = string(runes[:21]) + "..."
+	}
+
+	filled := 0
+	if event.Percent > 0 {
+		filled = int(event.Percent / 100 * multiBarWidth)
+		if filled > multiBarWidth {
+			filled = multiBarWidth
+		}
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", multiBarWidth-filled)
+
+	percentText := "  ?.?%"
+	if event.Percent >= 0 {
+		percentText = fmt.Sprintf("%5.1f%%", event.Percent)
+	}
+
+	switch event.Type {
+	case EventCompleted:
+		return fmt.Sprintf("%-24s [%s] 완료 %s", label, strings.Repeat("#", multiBarWidth), formatBytes(event.CurrentBytes))
+	case EventFailed:
+		return fmt.Sprintf("%-24s [%s] 실패: %s", label, bar, event.Message)
+	case EventProgress:
+		return fmt.Sprintf("%-24s [%s] %s %s %s/s 남은 시간 %s", label, bar, percentText,
+			formatBytes(event.CurrentBytes), formatBytes(int64(event.BytesPerSecond)),
+			utils.SecondsToHms(int(event.ETASeconds)))
+	default:
+		return fmt.Sprintf("%-24s [%s] 대기 중", label, bar)
+	}
+}
@@ -0,0 +1,100 @@
+package downloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// ResumeState 중단된 다운로드를 이어받기 위한 정보
+type ResumeState struct {
+	VodURL       string       `json:"vodURL"`
+	Quality      string       `json:"quality"`
+	OutputFile   string       `json:"outputFile"`
+	Backend      string       `json:"backend,omitempty"` // 받던 백엔드 (이어받기 방식 확인용)
+	SavedSeconds float64      `json:"savedSeconds"`      // 저장된 미디어 길이 (초)
+	SavedBytes   int64        `json:"savedBytes"`
+	Gaps         []SegmentGap `json:"gaps,omitempty"` // 지금까지 받지 못해 건너뛴 세그먼트
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// ResumeStatePath 출력 파일의 이어받기 정보 파일 경로
+func ResumeStatePath(outputFile string) string {
+	return outputFile + ".resume.json"
+}
+
+// LoadResumeState 이어받기 정보를 불러오는 함수 (없으면 nil 반환)
+func LoadResumeState(outputFile string) (*ResumeState, error) {
+	data, err := os.ReadFile(ResumeStatePath(outputFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state ResumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("이어받기 정보 파싱 실패: %v", err)
+	}
+
+	return &state, nil
+}
+
+// SaveResumeState 이어받기 정보를 저장하는 함수
+func SaveResumeState(state *ResumeState) error {
+	state.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(ResumeStatePath(state.OutputFile), data, 0644)
+}
+
+// RemoveResumeState 이어받기 정보를 삭제하는 함수
+func RemoveResumeState(outputFile string) {
+	os.Remove(ResumeStatePath(outputFile))
+}
+
+// InterruptedError 다운로드가 취소되었을 때 저장된 내용을 알려주는 오류
+type InterruptedError struct {
+	OutputFile   string
+	PlayableFile string // 받은 부분을 재생할 수 있는 파일 (.part를 그대로 재생할 수 없어 따로 저장한 경우)
+	SavedSeconds float64
+	SavedBytes   int64
+	Cause        error
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("다운로드가 중단되었습니다 (저장됨: %s, %s)",
+		utils.SecondsToHms(int(e.SavedSeconds)), formatBytes(e.SavedBytes))
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Cause
+}
+
+// errStalled 진행이 멈춰 다운로드를 중단한 경우 (다시 시도할 수 있음)
+var errStalled = errors.New("다운로드 진행이 멈췄습니다")
+
+// StalledError 진행이 멈춰 다시 시도했지만 허용 횟수를 넘은 경우의 오류
+type StalledError struct {
+	Timeout      time.Duration
+	Restarts     int
+	SavedSeconds float64
+}
+
+func (e *StalledError) Error() string {
+	return fmt.Sprintf("%s 동안 진행이 없는 상태가 반복되어 다운로드를 포기했습니다 (다시 시도 %d회, 저장됨: %s)",
+		e.Timeout, e.Restarts, utils.SecondsToHms(int(e.SavedSeconds)))
+}
+
+func (e *StalledError) Unwrap() error {
+	return errStalled
+}
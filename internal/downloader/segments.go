@@ -0,0 +1,195 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 세그먼트를 받지 못했을 때 다시 시도하는 횟수
+const segmentRetries = 3
+
+// DefaultSegmentWorkers 동시에 받는 세그먼트 수 기본값
+const DefaultSegmentWorkers = 4
+
+// MaxSegmentWorkers 동시에 받는 세그먼트 수 최대값 (서버 부담과 메모리 사용량 제한)
+const MaxSegmentWorkers = 16
+
+// indexedSegment 받을 세그먼트
+type indexedSegment struct {
+	sequence int64 // 미디어 시퀀스 번호 (초기화 세그먼트는 -1)
+	url      string
+	offset   float64 // 영상 시작부터의 위치 (초)
+	duration float64
+	mpegTS   bool // MPEG-TS 세그먼트면 패킷 단위로 확인
+}
+
+// segmentData 받은 세그먼트 내용 (실패하면 err)
+type segmentData struct {
+	data []byte
+	err  error
+}
+
+// fetchSegmentsOrdered 세그먼트를 workers개씩 동시에 받아 재생목록 순서대로 w에 기록하는 함수
+// 아직 기록하지 않은 세그먼트는 최대 workers개만 메모리에 두므로
+// 기록이 멈추면(일시 정지, ffmpeg 대기) 새 세그먼트도 받지 않음
+// 다시 시도해도 받지 못한 세그먼트는 skipLost면 빠진 구간으로 기록하고 넘어가며, 아니면 *SegmentLostError를 반환
+func fetchSegmentsOrdered(ctx context.Context, w io.Writer, segments []indexedSegment, workers int, skipLost bool, report *jobReporter) ([]SegmentGap, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > MaxSegmentWorkers {
+		workers = MaxSegmentWorkers
+	}
+
+	// 기록에 실패하면 남은 세그먼트 받기를 멈춤
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 세그먼트마다 결과를 받을 채널 (순서대로 기다리며 기록)
+	results := make([]chan segmentData, len(segments))
+	for i := range results {
+		results[i] = make(chan segmentData, 1)
+	}
+
+	// slots: 받았지만 아직 기록하지 않은 세그먼트 수 제한 (재정렬 버퍼 크기)
+	slots := make(chan struct{}, workers)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				data, err := downloadSegment(ctx, segments[i], report)
+				results[i] <- segmentData{data: data, err: err}
+			}
+		}()
+	}
+
+	// 빈 자리가 생길 때마다 다음 세그먼트를 작업자에게 넘김
+	go func() {
+		defer close(jobs)
+		for i := range segments {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var gaps []SegmentGap
+	var writeErr error
+	for i, segment := range segments {
+		var result segmentData
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			writeErr = ctx.Err()
+		}
+		if writeErr != nil {
+			break
+		}
+		if result.err != nil {
+			// 초기화 세그먼트가 없으면 나머지도 재생할 수 없음
+			if ctx.Err() != nil || segment.sequence < 0 {
+				writeErr = result.err
+				break
+			}
+			gap := SegmentGap{
+				Sequence: segment.sequence,
+				Offset:   segment.offset,
+				Duration: segment.duration,
+				Reason:   result.err.Error(),
+			}
+			if !skipLost {
+				writeErr = &SegmentLostError{Gap: gap}
+				break
+			}
+			report.warn("세그먼트 %s을(를) 받지 못해 건너뜁니다: %s", gap, gap.Reason)
+			gaps = append(gaps, gap)
+			<-slots
+			continue
+		}
+		if _, err := w.Write(result.data); err != nil {
+			writeErr = err
+			break
+		}
+		<-slots
+	}
+
+	cancel()
+	wg.Wait()
+	return gaps, writeErr
+}
+
+// downloadSegment 세그먼트 하나를 메모리로 받는 함수
+// 요청이 실패하거나 받은 내용이 온전하지 않으면 segmentRetries번까지 다시 시도
+func downloadSegment(ctx context.Context, segment indexedSegment, report *jobReporter) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= segmentRetries; attempt++ {
+		if attempt > 0 {
+			report.report(ProgressEvent{
+				Type:    EventSegmentRetried,
+				Percent: -1,
+				Segment: segment.sequence,
+				Attempt: attempt,
+				Message: lastErr.Error(),
+			})
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		data, err := getSegment(ctx, segment)
+		if err == nil {
+			return data, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%d번 시도 후 실패: %v", segmentRetries+1, lastErr)
+}
+
+// getSegment 세그먼트를 요청해 내용을 확인한 뒤 반환
+func getSegment(ctx context.Context, segment indexedSegment) ([]byte, error) {
+	req, err := newMediaRequest(ctx, segment.url)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("세그먼트 요청 실패: %s", resp.Status)
+	}
+
+	var buf bytes.Buffer
+	if resp.ContentLength > 0 {
+		buf.Grow(int(resp.ContentLength))
+	}
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+	if err := validateSegment(buf.Bytes(), resp.ContentLength, segment.mpegTS); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
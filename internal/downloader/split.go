@@ -0,0 +1,378 @@
+package downloader
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/config"
+	"chzzk-downloader/internal/utils"
+)
+
+// 기본 분할 파일명 템플릿 ({name}: 원본 파일명, {part}: 001부터 시작하는 파트 번호)
+const DefaultSplitTemplate = "{name} part{part}"
+
+// 크기 기준 분할 시 키프레임 위치 오차를 고려해 목표 크기보다 조금 작게 자름
+const splitSizeMargin = 0.95
+
+// 크기 기준 분할에서 목표 크기를 넘는 파트를 다시 나누는 최대 횟수
+const maxSplitRetries = 4
+
+// 파트마다 챕터를 기록할 때 늘어나는 크기를 고려해 남겨 두는 여유 (바이트)
+const splitChapterReserve = 64 * 1024
+
+// SplitOptions 출력 파일 분할 옵션 (Duration과 Size 중 하나만 사용)
+type SplitOptions struct {
+	Duration     time.Duration // 파트당 길이
+	Size         int64         // 파트당 최대 크기 (바이트)
+	NameTemplate string        // 파트 파일명 템플릿 (비어 있으면 DefaultSplitTemplate)
+}
+
+// SplitPart 분할된 파트 하나의 정보
+type SplitPart struct {
+	Index       int     `json:"index"`
+	File        string  `json:"file"`
+	StartOffset float64 `json:"startOffset"` // 원본 기준 시작 위치 (초)
+	Start       string  `json:"start"`       // 원본 기준 시작 위치 (시:분:초)
+	Duration    float64 `json:"duration"`
+	Size        int64   `json:"size"`
+}
+
+// SplitManifest 분할 결과 목록 (<파일명>.parts.json)
+type SplitManifest struct {
+	Source string      `json:"source"`
+	Parts  []SplitPart `json:"parts"`
+}
+
+// ParseSplitOptions "1h", "90m" 같은 길이 또는 "2GiB", "500MB" 같은 크기를 분할 옵션으로 변환
+func ParseSplitOptions(spec string) (*SplitOptions, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d < time.Minute {
+			return nil, fmt.Errorf("분할 길이는 1분 이상이어야 합니다: %s", spec)
+		}
+		return &SplitOptions{Duration: d}, nil
+	}
+
+	size, err := utils.ParseByteSize(spec)
+	if err != nil {
+		return nil, fmt.Errorf("분할 기준을 해석할 수 없습니다: %s (예: 1h, 30m, 2GiB, 500MB)", spec)
+	}
+	if size < 10*1024*1024 {
+		return nil, fmt.Errorf("분할 크기는 10MiB 이상이어야 합니다: %s", spec)
+	}
+	return &SplitOptions{Size: size}, nil
+}
+
+// String 분할 옵션 설명
+func (o *SplitOptions) String() string {
+	if o.Duration > 0 {
+		return fmt.Sprintf("%s마다", utils.SecondsToHms(int(o.Duration.Seconds())))
+	}
+	return fmt.Sprintf("%s마다", formatBytes(o.Size))
+}
+
+// splitManifestSuffix 분할 목록 파일의 접미사
+const splitManifestSuffix = ".parts.json"
+
+// SplitManifestPath 분할 목록 파일 경로
+func SplitManifestPath(inputFile string) string {
+	return strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + splitManifestSuffix
+}
+
+// loadSplitManifest 분할 목록 파일을 읽는 함수
+func loadSplitManifest(path string) (*SplitManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest SplitManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("분할 목록 파싱 실패: %v", err)
+	}
+	return &manifest, nil
+}
+
+// SplitFile 파일을 키프레임 경계에서 재인코딩 없이 여러 파트로 나누는 함수
+// duration은 원본 영상 길이(초)로, 크기 기준 분할 시 평균 비트레이트 계산에 사용
+// 크기 기준이면 목표 크기를 넘는 파트를 더 짧게 다시 나누며, 그래도 넘으면 원본을 남기고 오류 반환
+// chapters가 있으면 원본의 챕터 대신 파트마다 그 파트에 걸친 챕터만 파트 기준 위치로 옮겨 기록
+// 분할에 성공하면 원본 파일을 삭제하고 목록 파일을 기록
+func SplitFile(ctx context.Context, inputFile string, split *SplitOptions, duration int, container Container, chapters []Chapter) (*SplitManifest, error) {
+	segmentTime := split.Duration.Seconds()
+	if split.Size > 0 {
+		fileStat, err := os.Stat(inputFile)
+		if err != nil {
+			return nil, err
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("영상 길이를 알 수 없어 크기 기준으로 분할할 수 없습니다")
+		}
+		if fileStat.Size() <= split.Size {
+			// 나눌 필요가 없음
+			return nil, nil
+		}
+		bytesPerSecond := float64(fileStat.Size()) / float64(duration)
+		segmentTime = float64(split.Size) / bytesPerSecond * splitSizeMargin
+	} else if duration > 0 && float64(duration) <= segmentTime {
+		return nil, nil
+	}
+
+	template := split.NameTemplate
+	if template == "" {
+		template = DefaultSplitTemplate
+	}
+	if !strings.Contains(template, "{part}") {
+		return nil, fmt.Errorf("분할 파일명 템플릿에 {part}가 없습니다: %s", template)
+	}
+	if container == "" {
+		container = DefaultContainer
+	}
+
+	// 파트는 임시 폴더에 만든 뒤 모두 준비되면 최종 이름으로 옮김 (실패하면 임시 폴더만 지우고 원본은 남김)
+	ext := filepath.Ext(inputFile)
+	tempDir := strings.TrimSuffix(inputFile, ext) + ".split"
+	if err := os.RemoveAll(tempDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// TS는 챕터를 지원하지 않으므로 파트별 챕터도 기록하지 않음
+	if container == ContainerTS {
+		chapters = nil
+	}
+	withChapters := len(chapters) > 0
+
+	pieces, err := segmentFile(ctx, inputFile, filepath.Join(tempDir, "part"), segmentTime, container, withChapters)
+	if err != nil {
+		return nil, err
+	}
+	if split.Size > 0 {
+		// 나중에 챕터를 기록하면 파일이 조금 커지므로 그만큼 여유를 둠
+		limit := split.Size
+		if withChapters {
+			limit -= splitChapterReserve
+		}
+		var fitted []splitPiece
+		for _, piece := range pieces {
+			parts, err := fitSplitPiece(ctx, piece, limit, container, withChapters, 0)
+			if err != nil {
+				return nil, fmt.Errorf("%v (원본 파일은 그대로 남아 있습니다)", err)
+			}
+			fitted = append(fitted, parts...)
+		}
+		pieces = fitted
+	}
+
+	// 최종 파트 이름으로 옮기기
+	name := strings.TrimSuffix(filepath.Base(inputFile), ext)
+	manifest := &SplitManifest{Source: filepath.Base(inputFile)}
+	for i, piece := range pieces {
+		partName := strings.ReplaceAll(template, "{name}", name)
+		partName = strings.ReplaceAll(partName, "{part}", fmt.Sprintf("%03d", i+1))
+		partFile := filepath.Join(filepath.Dir(inputFile), utils.SanitizeFilename(partName+ext))
+		if err := os.Rename(piece.file, partFile); err != nil {
+			return nil, fmt.Errorf("파트 파일 이동 실패: %v", err)
+		}
+		if withChapters {
+			partChapters := chaptersForPart(chapters, piece.start, piece.start+piece.duration)
+			if err := WriteChapters(ctx, partFile, partChapters, container); err != nil {
+				return nil, fmt.Errorf("파트 %d 챕터 기록 실패: %v", i+1, err)
+			}
+			if fileStat, err := os.Stat(partFile); err == nil {
+				piece.size = fileStat.Size()
+			}
+		}
+
+		manifest.Parts = append(manifest.Parts, SplitPart{
+			Index:       i + 1,
+			File:        filepath.Base(partFile),
+			StartOffset: piece.start,
+			Start:       utils.SecondsToHms(int(piece.start)),
+			Duration:    piece.duration,
+			Size:        piece.size,
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(SplitManifestPath(inputFile), data, 0644); err != nil {
+		return nil, err
+	}
+
+	// 모든 파트가 만들어졌으므로 원본 삭제
+	if err := os.Remove(inputFile); err != nil {
+		return manifest, fmt.Errorf("원본 파일 삭제 실패: %v", err)
+	}
+
+	return manifest, nil
+}
+
+// splitPiece 분할 중인 파트 하나 (임시 폴더의 파일)
+type splitPiece struct {
+	file     string
+	start    float64 // 원본 기준 시작 위치 (초)
+	duration float64
+	size     int64
+}
+
+// fitSplitPiece 크기 기준 분할에서 limit보다 큰 파트를 그 파트의 비트레이트로 다시 나눔
+// 평균 비트레이트로 정한 길이는 VBR 영상에서 목표 크기를 넘을 수 있으므로 모든 파트가 limit 이하가 될 때까지 반복
+func fitSplitPiece(ctx context.Context, piece splitPiece, limit int64, container Container, dropChapters bool, depth int) ([]splitPiece, error) {
+	if piece.size <= limit {
+		return []splitPiece{piece}, nil
+	}
+
+	tooLarge := fmt.Errorf("%s 부근 파트가 %s로 분할 크기 %s를 넘으며, 키프레임 간격이 길어 더 작게 나눌 수 없습니다",
+		utils.SecondsToHms(int(piece.start)), formatBytes(piece.size), formatBytes(limit))
+	if depth >= maxSplitRetries || piece.duration <= 0 {
+		return nil, tooLarge
+	}
+
+	bytesPerSecond := float64(piece.size) / piece.duration
+	segmentTime := float64(limit) / bytesPerSecond * splitSizeMargin
+	subPieces, err := segmentFile(ctx, piece.file, strings.TrimSuffix(piece.file, filepath.Ext(piece.file))+"-", segmentTime, container, dropChapters)
+	if err != nil {
+		return nil, err
+	}
+	if len(subPieces) < 2 {
+		// 키프레임이 없어 나뉘지 않음
+		return nil, tooLarge
+	}
+	os.Remove(piece.file)
+
+	var fitted []splitPiece
+	for _, sub := range subPieces {
+		sub.start += piece.start
+		parts, err := fitSplitPiece(ctx, sub, limit, container, dropChapters, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		fitted = append(fitted, parts...)
+	}
+	return fitted, nil
+}
+
+// segmentFile ffmpeg segment로 inputFile을 segmentTime(초)마다 나눔
+// 파트는 <prefix>001<확장자>부터 만들어지며, 시작 위치는 inputFile 기준
+// dropChapters면 원본 챕터를 파트에 복사하지 않음 (파트마다 따로 기록하는 경우)
+func segmentFile(ctx context.Context, inputFile, prefix string, segmentTime float64, container Container, dropChapters bool) ([]splitPiece, error) {
+	ext := filepath.Ext(inputFile)
+	listFile := prefix + "list.csv"
+	defer os.Remove(listFile)
+
+	args := []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-i", inputFile,
+		"-map", "0",
+		"-c", "copy",
+		"-f", "segment",
+		"-segment_time", strconv.FormatFloat(segmentTime, 'f', 3, 64),
+		"-segment_start_number", "1",
+		"-reset_timestamps", "1",
+		"-segment_format", container.muxer(),
+		"-segment_list", listFile,
+		"-segment_list_type", "csv",
+		"-y",
+	}
+	if flags := container.movflags(); flags != "" {
+		args = append(args, "-segment_format_options", "movflags="+flags)
+	}
+	if dropChapters {
+		args = append(args, "-map_chapters", "-1")
+	}
+
+	// 출력 패턴 (임시 폴더 안이지만 경로의 %는 이스케이프)
+	pattern := strings.ReplaceAll(prefix, "%", "%%") + "%03d" + ext
+	cmd := exec.CommandContext(ctx, config.GetFFmpeg(), append(args, pattern)...)
+	detachFromConsoleSignals(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ffmpeg 분할 실패: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return readSegmentList(listFile, filepath.Dir(prefix))
+}
+
+// chaptersForPart 원본 기준 챕터 중 [start, end) 구간에 걸친 챕터만 골라 파트 기준 위치로 옮김
+// 앞 파트에서 시작해 이어지는 챕터는 파트 시작(0초)부터, 다음 파트로 넘어가는 챕터는 파트 끝까지로 자름
+func chaptersForPart(chapters []Chapter, start, end float64) []Chapter {
+	var part []Chapter
+	for i, chapter := range chapters {
+		chapterEnd := chapter.End
+		if chapterEnd <= chapter.Start {
+			chapterEnd = math.Inf(1)
+			if i+1 < len(chapters) {
+				chapterEnd = chapters[i+1].Start
+			}
+		}
+		if chapterEnd <= start || chapter.Start >= end {
+			continue
+		}
+		part = append(part, Chapter{
+			Start: math.Max(chapter.Start, start) - start,
+			End:   math.Min(chapterEnd, end) - start,
+			Title: chapter.Title,
+		})
+	}
+	return part
+}
+
+// readSegmentList ffmpeg segment 목록(csv: 파일명,시작,끝)을 읽어 파트 목록 생성
+func readSegmentList(listFile, dir string) ([]splitPiece, error) {
+	f, err := os.Open(listFile)
+	if err != nil {
+		return nil, fmt.Errorf("분할 목록 읽기 실패: %v", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("분할 목록 파싱 실패: %v", err)
+	}
+
+	var pieces []splitPiece
+	for _, record := range records {
+		if len(record) < 3 {
+			continue
+		}
+		start, _ := strconv.ParseFloat(record[1], 64)
+		end, _ := strconv.ParseFloat(record[2], 64)
+
+		piece := splitPiece{
+			file:     filepath.Join(dir, filepath.Base(record[0])),
+			start:    start,
+			duration: end - start,
+		}
+		fileStat, err := os.Stat(piece.file)
+		if err != nil {
+			return nil, fmt.Errorf("분할된 파트를 찾을 수 없습니다: %v", err)
+		}
+		piece.size = fileStat.Size()
+		pieces = append(pieces, piece)
+	}
+
+	if len(pieces) == 0 {
+		return nil, fmt.Errorf("분할된 파트가 없습니다")
+	}
+
+	return pieces, nil
+}
@@ -0,0 +1,228 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/utils"
+)
+
+// ffmpeg가 중단 요청 후 파일을 마무리할 때까지 기다리는 최대 시간
+const ffmpegFinalizeTimeout = 30 * time.Second
+
+// DownloadStream 백엔드로 스트림을 받아 outputFile에 저장하는 함수
+// 받는 동안에는 <파일명>.part에 저장하고, 진행이 멈추면 마지막으로 저장된 위치부터 다시 받음
+// ctx가 취소되면 받은 부분까지 마무리한 뒤 이어받기 정보를 기록하고 *InterruptedError를 반환
+// 받지 못해 건너뛴 세그먼트가 있으면 이어받기 전 부분까지 포함해 반환
+func DownloadStream(ctx context.Context, backend Backend, stream *Stream, outputFile string, options *DownloadOptions) ([]SegmentGap, error) {
+	report := newJobReporter(options, outputFile)
+
+	mediaURL, err := backend.Resolve(ctx, stream)
+	if err != nil {
+		return nil, report.failf("%s 백엔드 주소 확인 실패: %v", backend.Name(), err)
+	}
+
+	// 이어받기: 이전에 저장된 위치부터 받음
+	var resume *ResumeState
+	if options.ResumeOption == "--continue" {
+		state, err := LoadResumeState(outputFile)
+		if err != nil {
+			report.warn("이어받기 정보를 읽지 못했습니다: %v", err)
+		}
+		switch {
+		case state == nil || (state.SavedSeconds <= 0 && state.SavedBytes <= 0):
+			report.warn("이어받기 정보가 없어 처음부터 다시 받습니다.")
+		case !sameResumeKind(state.Backend, backend):
+			// 바이트 단위로 이어 쓴 파일과 시간 단위로 이어붙이는 파일은 서로 이어받을 수 없음
+			report.warn("이전에 %s 백엔드로 받던 파일이라 %s 백엔드로는 이어받을 수 없어 처음부터 다시 받습니다.", state.Backend, backend.Name())
+		default:
+			resume = state
+		}
+	}
+
+	partFile := PartPath(outputFile)
+
+	// 진행이 멈추면 마지막으로 저장된 위치부터 다시 받고, MaxRestarts번을 넘으면 포기
+	var result transferResult
+	var runErr error
+	var savedSeconds float64
+	var gaps []SegmentGap
+	if resume != nil {
+		gaps = resume.Gaps
+	}
+	for restarts := 0; ; restarts++ {
+		var startOffset float64
+		if resume != nil && !resumesByBytes(backend) {
+			startOffset = resume.SavedSeconds
+		}
+
+		result, runErr = downloadAttempt(ctx, backend, mediaURL, stream, outputFile, resume, options, report)
+		savedSeconds = startOffset + result.outTime
+		gaps = mergeGaps(gaps, result.gaps)
+
+		if !errors.Is(runErr, errStalled) || ctx.Err() != nil {
+			break
+		}
+		if restarts >= options.MaxRestarts {
+			runErr = &StalledError{Timeout: options.StallTimeout, Restarts: restarts, SavedSeconds: savedSeconds}
+			break
+		}
+
+		resume = &ResumeState{
+			VodURL:       options.VodURL,
+			Quality:      options.Quality,
+			OutputFile:   outputFile,
+			Backend:      backend.Name(),
+			SavedSeconds: savedSeconds,
+			Gaps:         gaps,
+		}
+		if fileStat, err := os.Stat(partFile); err == nil {
+			resume.SavedBytes = fileStat.Size()
+		}
+		if resume.SavedSeconds <= 0 && resume.SavedBytes <= 0 {
+			resume = nil
+		}
+		report.warn("%s 동안 진행이 없어 %s부터 다시 받습니다 (%d/%d)",
+			options.StallTimeout, utils.SecondsToHms(int(savedSeconds)), restarts+1, options.MaxRestarts)
+	}
+
+	var savedBytes int64
+	if fileStat, err := os.Stat(partFile); err == nil {
+		savedBytes = fileStat.Size()
+	}
+
+	if ctx.Err() != nil || runErr != nil {
+		// 저장된 부분이 있으면 다음에 이어받을 수 있도록 기록
+		if savedSeconds > 0 && savedBytes > 0 {
+			if err := SaveResumeState(&ResumeState{
+				VodURL:       options.VodURL,
+				Quality:      options.Quality,
+				OutputFile:   outputFile,
+				Backend:      backend.Name(),
+				SavedSeconds: savedSeconds,
+				SavedBytes:   savedBytes,
+				Gaps:         gaps,
+			}); err != nil {
+				report.warn("이어받기 정보 저장 실패: %v", err)
+			}
+		} else {
+			os.Remove(partFile)
+		}
+
+		if ctx.Err() != nil {
+			return gaps, report.fail(&InterruptedError{
+				OutputFile:   partFile,
+				PlayableFile: result.playable,
+				SavedSeconds: savedSeconds,
+				SavedBytes:   savedBytes,
+				Cause:        ctx.Err(),
+			})
+		}
+		return gaps, report.fail(runErr)
+	}
+
+	if err := commitPartFile(partFile, outputFile); err != nil {
+		return gaps, report.failf("받은 파일을 최종 이름으로 바꾸지 못했습니다 (%s 파일은 보존됨): %v", partFile, err)
+	}
+	RemoveResumeState(outputFile)
+	os.Remove(PartialPath(outputFile))
+
+	report.report(ProgressEvent{
+		Type:           EventCompleted,
+		CurrentBytes:   savedBytes,
+		Percent:        100,
+		BytesPerSecond: float64(result.size) / result.elapsed.Seconds(),
+		ElapsedSeconds: result.elapsed.Seconds(),
+		OutTime:        savedSeconds,
+		Duration:       stream.Duration,
+	})
+
+	return gaps, nil
+}
+
+// sameResumeKind 이전에 받던 백엔드와 이어받는 방식(바이트/시간)이 같은지 확인
+func sameResumeKind(previous string, backend Backend) bool {
+	if previous == "" {
+		// 백엔드를 기록하지 않던 이전 버전은 streamlink로 받은 파일
+		previous = (&streamlinkBackend{}).Name()
+	}
+	for _, b := range Backends {
+		if b.Name() == previous {
+			return resumesByBytes(b) == resumesByBytes(backend)
+		}
+	}
+	return false
+}
+
+// downloadAttempt 한 번의 다운로드 시도
+// resume이 있으면 바이트 단위 백엔드는 기존 파일 뒤에 이어 쓰고,
+// 시간 단위 백엔드는 저장된 위치부터 별도 파일로 받아 이어붙임
+func downloadAttempt(ctx context.Context, backend Backend, mediaURL string, stream *Stream, outputFile string, resume *ResumeState, options *DownloadOptions, report *jobReporter) (transferResult, error) {
+	partFile := PartPath(outputFile)
+	job := &transferJob{
+		URL:        mediaURL,
+		Stream:     stream,
+		TargetFile: partFile,
+		Options:    options,
+		Report:     report,
+	}
+
+	if resume == nil {
+		return backend.Download(ctx, job)
+	}
+
+	var baseBytes int64
+	if fileStat, err := os.Stat(partFile); err == nil {
+		baseBytes = fileStat.Size()
+	}
+
+	if resumesByBytes(backend) {
+		job.StartBytes = baseBytes
+		return backend.Download(ctx, job)
+	}
+
+	ext := filepath.Ext(outputFile)
+	contFile := PartPath(strings.TrimSuffix(outputFile, ext) + ".cont" + ext)
+	job.TargetFile = contFile
+	job.StartOffset = resume.SavedSeconds
+	job.BaseBytes = baseBytes
+
+	result, runErr := backend.Download(ctx, job)
+
+	// 이어받은 부분을 기존 파일 뒤에 붙임
+	if result.size > 0 {
+		if err := appendContinuation(ctx, partFile, contFile, outputContainer(options)); err != nil {
+			// 기존 파일에는 이어받기 시작 위치까지만 저장되어 있음
+			result.outTime = 0
+			return result, fmt.Errorf("이어받은 파일 합치기 실패 (%s 파일은 보존됨): %v", contFile, err)
+		}
+	} else {
+		os.Remove(contFile)
+	}
+
+	return result, runErr
+}
+
+// appendContinuation 이어받은 파일을 기존 파일 뒤에 붙이고 이어받은 파일을 삭제
+func appendContinuation(ctx context.Context, outputFile, continuationFile string, container Container) error {
+	name := strings.TrimSuffix(outputFile, PartSuffix)
+	ext := filepath.Ext(name)
+	joinedFile := PartPath(strings.TrimSuffix(name, ext) + ".joined" + ext)
+
+	// 중단된 경우에도 지금까지 받은 부분은 합쳐야 하므로 취소되지 않는 컨텍스트 사용
+	if err := ConcatFiles(context.WithoutCancel(ctx), []string{outputFile, continuationFile}, joinedFile, container); err != nil {
+		os.Remove(joinedFile)
+		return err
+	}
+
+	if err := os.Rename(joinedFile, outputFile); err != nil {
+		return err
+	}
+	os.Remove(continuationFile)
+	return nil
+}
@@ -0,0 +1,374 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chzzk-downloader/internal/config"
+)
+
+// 트랜스코딩 실행 시점
+const (
+	TranscodeAfter  = "after"  // 다운로드 완료 후 별도 파일로 변환
+	TranscodeStream = "stream" // 다운로드하면서 바로 변환해서 저장
+)
+
+// TranscodeProfile 트랜스코딩 프로필 (profiles.json에 정의)
+type TranscodeProfile struct {
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	Mode           string    `json:"mode"`                   // after 또는 stream
+	VideoCodec     string    `json:"videoCodec"`             // libx264, libx265, copy, 비어 있으면 영상 제외
+	CRF            int       `json:"crf,omitempty"`          // 0이면 인코더 기본값
+	Preset         string    `json:"preset,omitempty"`       // ultrafast ~ veryslow
+	Height         int       `json:"height,omitempty"`       // 세로 해상도 (0이면 원본 유지)
+	FPS            int       `json:"fps,omitempty"`          // 프레임레이트 (0이면 원본 유지)
+	AudioCodec     string    `json:"audioCodec"`             // aac, libopus, copy, 비어 있으면 copy
+	AudioBitrate   string    `json:"audioBitrate,omitempty"` // 예: 128k
+	Container      Container `json:"container"`              // 출력 컨테이너
+	Extension      string    `json:"extension,omitempty"`    // 확장자 지정 (비어 있으면 컨테이너 기본값)
+	DeleteOriginal bool      `json:"deleteOriginal"`         // after 모드에서 변환 성공 시 원본 삭제
+}
+
+// 기본 트랜스코딩 프로필 (profiles.json이 없을 때 생성)
+var defaultTranscodeProfiles = []TranscodeProfile{
+	{
+		Name:        "archive-hevc",
+		Description: "보관용 HEVC (원본 해상도, CRF 26)",
+		Mode:        TranscodeAfter,
+		VideoCodec:  "libx265",
+		CRF:         26,
+		Preset:      "medium",
+		AudioCodec:  "copy",
+		Container:   ContainerMP4,
+	},
+	{
+		Name:         "mobile-720p",
+		Description:  "모바일 공유용 H.264 720p 30fps",
+		Mode:         TranscodeAfter,
+		VideoCodec:   "libx264",
+		CRF:          23,
+		Preset:       "veryfast",
+		Height:       720,
+		FPS:          30,
+		AudioCodec:   "aac",
+		AudioBitrate: "128k",
+		Container:    ContainerMP4,
+	},
+	{
+		Name:         "audio-opus",
+		Description:  "오디오만 Opus 96kbps",
+		Mode:         TranscodeStream,
+		AudioCodec:   "libopus",
+		AudioBitrate: "96k",
+		Container:    ContainerMKV,
+		Extension:    ".mka",
+	},
+}
+
+// LoadTranscodeProfiles 트랜스코딩 프로필 목록을 불러오는 함수
+// 프로필 파일이 없으면 기본 프로필로 파일을 만들어 수정할 수 있게 함
+func LoadTranscodeProfiles() ([]TranscodeProfile, error) {
+	profilesFile := filepath.Join(config.GetBaseDir(), config.TranscodeProfilesFile)
+
+	data, err := os.ReadFile(profilesFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		// 기본 프로필 파일 생성 (실패해도 기본 프로필은 사용 가능)
+		if data, err := json.MarshalIndent(defaultTranscodeProfiles, "", "  "); err == nil && os.MkdirAll(config.GetBaseDir(), 0755) == nil {
+			os.WriteFile(profilesFile, data, 0644)
+		}
+		return append([]TranscodeProfile(nil), defaultTranscodeProfiles...), nil
+	}
+
+	var profiles []TranscodeProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("%s 파싱 실패: %v", config.TranscodeProfilesFile, err)
+	}
+
+	for i := range profiles {
+		if err := profiles[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return profiles, nil
+}
+
+// FindTranscodeProfile 이름으로 트랜스코딩 프로필을 찾는 함수
+func FindTranscodeProfile(name string) (*TranscodeProfile, error) {
+	profiles, err := LoadTranscodeProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], nil
+		}
+		names = append(names, profiles[i].Name)
+	}
+
+	return nil, fmt.Errorf("트랜스코딩 프로필 '%s'을(를) 찾을 수 없습니다 (사용 가능: %s)", name, strings.Join(names, ", "))
+}
+
+// validate 프로필 값 검증 및 기본값 보정
+func (p *TranscodeProfile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("이름이 없는 트랜스코딩 프로필이 있습니다")
+	}
+
+	switch p.Mode {
+	case "":
+		p.Mode = TranscodeAfter
+	case TranscodeAfter, TranscodeStream:
+	default:
+		return fmt.Errorf("프로필 '%s': 알 수 없는 mode '%s' (after, stream 중 선택)", p.Name, p.Mode)
+	}
+
+	container, err := ParseContainer(string(p.Container))
+	if err != nil {
+		return fmt.Errorf("프로필 '%s': %v", p.Name, err)
+	}
+	p.Container = container
+
+	if p.VideoCodec == "" && (p.AudioCodec == "" || p.AudioCodec == "copy") && p.Mode == TranscodeAfter {
+		return fmt.Errorf("프로필 '%s': 영상과 음성 모두 변환하지 않는 프로필입니다", p.Name)
+	}
+
+	return nil
+}
+
+// OutputExtension 프로필 출력 파일 확장자
+func (p *TranscodeProfile) OutputExtension() string {
+	if p.Extension != "" {
+		return p.Extension
+	}
+	return p.Container.Extension()
+}
+
+// copiesAudio 음성을 재인코딩 없이 복사하는지 여부
+func (p *TranscodeProfile) copiesAudio() bool {
+	return p.AudioCodec == "" || p.AudioCodec == "copy"
+}
+
+// CodecArgs 프로필의 코덱 관련 ffmpeg 인자 (-c:v, -crf, -vf, -c:a ...)
+func (p *TranscodeProfile) CodecArgs() []string {
+	var args []string
+
+	// 영상
+	if p.VideoCodec == "" {
+		args = append(args, "-vn")
+	} else {
+		args = append(args, "-c:v", p.VideoCodec)
+		if p.VideoCodec != "copy" {
+			if p.CRF > 0 {
+				args = append(args, "-crf", strconv.Itoa(p.CRF))
+			}
+			if p.Preset != "" {
+				args = append(args, "-preset", p.Preset)
+			}
+
+			var filters []string
+			if p.Height > 0 {
+				filters = append(filters, fmt.Sprintf("scale=-2:%d", p.Height))
+			}
+			if p.FPS > 0 {
+				filters = append(filters, fmt.Sprintf("fps=%d", p.FPS))
+			}
+			if len(filters) > 0 {
+				args = append(args, "-vf", strings.Join(filters, ","))
+			}
+
+			// HEVC를 MP4에 넣을 때 애플 기기 호환을 위해 hvc1 태그 사용
+			if p.VideoCodec == "libx265" && p.Container != ContainerMKV && p.Container != ContainerTS {
+				args = append(args, "-tag:v", "hvc1")
+			}
+		}
+	}
+
+	// 음성
+	if p.copiesAudio() {
+		args = append(args, "-c:a", "copy")
+	} else {
+		args = append(args, "-c:a", p.AudioCodec)
+		if p.AudioBitrate != "" {
+			args = append(args, "-b:a", p.AudioBitrate)
+		}
+	}
+
+	return args
+}
+
+// MuxArgs HLS(MPEG-TS) 입력을 이 프로필로 저장할 때의 컨테이너 인자
+func (p *TranscodeProfile) MuxArgs() []string {
+	// ADTS 변환은 음성을 그대로 복사할 때만 필요
+	if p.copiesAudio() {
+		return p.Container.MuxArgs()
+	}
+	return p.Container.FormatArgs()
+}
+
+// TranscodeOutputPath 다운로드한 파일을 변환할 때의 출력 경로 (예: 제목.archive-hevc.mp4)
+func TranscodeOutputPath(inputFile string, profile *TranscodeProfile) string {
+	base := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
+	return base + "." + profile.Name + profile.OutputExtension()
+}
+
+// TranscodeFile 다운로드 완료된 파일을 프로필에 맞게 변환하는 함수
+// duration은 진행률 계산용 영상 길이(초)이며, 변환된 파일 경로를 반환
+// 변환할 파일이 이미 있으면 다운로드와 같은 중복 파일 처리 방법(-on-duplicate)을 따르며,
+// 건너뛰면 기존 변환 파일 경로를 반환
+func TranscodeFile(ctx context.Context, inputFile string, profile *TranscodeProfile, duration int, options *DownloadOptions) (string, error) {
+	outputFile := TranscodeOutputPath(inputFile, profile)
+	report := newJobReporter(options, outputFile)
+
+	duplicate, err := resolveDuplicate(outputFile, options, report)
+	if err != nil {
+		return "", report.fail(err)
+	}
+	if duplicate.skip {
+		return outputFile, nil
+	}
+	// 변환은 이어서 할 수 없으므로 이어받기로 옮긴 파일은 처음부터 다시 만듦
+	if duplicate.resume {
+		RemoveResumeState(outputFile)
+	}
+	outputFile = duplicate.outputFile
+	partFile := PartPath(outputFile)
+
+	args := []string{
+		"-i", inputFile,
+		"-y",
+		"-nostats",
+		"-progress", "pipe:1",
+		"-loglevel", "warning",
+	}
+	args = append(args, profile.CodecArgs()...)
+	args = append(args, profile.Container.FormatArgs()...)
+
+	cmd := exec.CommandContext(ctx, config.GetFFmpeg(), append(args, partFile)...)
+	detachFromConsoleSignals(cmd)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", report.failf("ffmpeg stdout pipe 생성 실패: %v", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", report.failf("ffmpeg stderr pipe 생성 실패: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", report.failf("ffmpeg 실행 실패: %v", err)
+	}
+
+	report.report(ProgressEvent{
+		Type:     EventStarted,
+		Percent:  -1,
+		Duration: duration,
+		Message:  fmt.Sprintf("트랜스코딩 (%s)\nffmpeg CMD: %s", profile.Name, cmd.String()),
+	})
+
+	startedAt := time.Now()
+	var wg sync.WaitGroup
+
+	// 변환 진행 상황 전달
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		lastReport := time.Time{}
+		readFFmpegProgress(stdout, func(p ffmpegProgress) {
+			if time.Since(lastReport) < 500*time.Millisecond && !p.End {
+				return
+			}
+			lastReport = time.Now()
+
+			elapsed := time.Since(startedAt)
+			percent, totalBytes, eta := estimateProgress(p.OutTime, duration, p.TotalSize, elapsed)
+			report.report(ProgressEvent{
+				Type:           EventProgress,
+				CurrentBytes:   p.TotalSize,
+				TotalBytes:     totalBytes,
+				Percent:        percent,
+				BytesPerSecond: float64(p.TotalSize) / elapsed.Seconds(),
+				ETASeconds:     eta.Seconds(),
+				ElapsedSeconds: elapsed.Seconds(),
+				OutTime:        p.OutTime,
+				Duration:       duration,
+			})
+		})
+	}()
+
+	// ffmpeg 로그 처리 (경고 이상만 출력됨)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			report.warn("[FFMPEG] %s", scanner.Text())
+		}
+	}()
+
+	wg.Wait()
+	if err := cmd.Wait(); err != nil {
+		// 중간에 끊긴 변환 결과는 사용할 수 없으므로 삭제
+		os.Remove(partFile)
+		if ctx.Err() != nil {
+			return "", report.fail(ctx.Err())
+		}
+		return "", report.failf("트랜스코딩 실패: %v", err)
+	}
+
+	if err := commitPartFile(partFile, outputFile); err != nil {
+		return "", report.failf("변환된 파일을 최종 이름으로 바꾸지 못했습니다: %v", err)
+	}
+
+	// keep-larger: 새로 변환한 파일과 기존 파일 중 더 큰 파일을 기존 이름으로 남김
+	if duplicate.existingFile != "" {
+		kept, err := keepLarger(outputFile, duplicate.existingFile)
+		if err != nil {
+			return "", report.fail(err)
+		}
+		if !kept {
+			report.warn("기존 파일이 더 커서 새로 변환한 파일을 삭제했습니다: %s", duplicate.existingFile)
+		}
+		outputFile = duplicate.existingFile
+	}
+
+	var size int64
+	if fileStat, err := os.Stat(outputFile); err == nil {
+		size = fileStat.Size()
+	}
+	elapsed := time.Since(startedAt)
+	report.report(ProgressEvent{
+		Type:           EventCompleted,
+		CurrentBytes:   size,
+		Percent:        100,
+		BytesPerSecond: float64(size) / elapsed.Seconds(),
+		ElapsedSeconds: elapsed.Seconds(),
+		OutTime:        float64(duration),
+		Duration:       duration,
+	})
+
+	if profile.DeleteOriginal {
+		if err := os.Remove(inputFile); err != nil {
+			report.warn("원본 파일 삭제 실패: %v", err)
+		}
+	}
+
+	return outputFile, nil
+}
@@ -1,12 +1,44 @@
 package downloader
 
+import "time"
+
 // DownloadOptions 다운로드 옵션을 담는 구조체
 type DownloadOptions struct {
-	VodURL          string
-	Quality         string
-	OutputFolder    string
-	Filename        string
-	SpeedOption     string
-	DownloadSection string
-	ResumeOption    string
+	VodURL           string
+	Quality          string
+	OutputFolder     string
+	Filename         string
+	SpeedOption      string
+	DownloadSection  string
+	ResumeOption     string
+	Duplicate        DuplicatePolicy   // 출력 파일이 이미 있을 때의 처리 방법 (비어 있으면 ask)
+	Prompter         DuplicatePrompter // ask 정책에서 처리 방법을 묻는 대상 (nil이면 건너뜀)
+	Container        Container         // 출력 컨테이너 (비어 있으면 MP4)
+	Profile          *TranscodeProfile // 트랜스코딩 프로필 (nil이면 원본 그대로 저장)
+	Split            *SplitOptions     // 파트 분할 옵션 (nil이면 분할하지 않음)
+	Chapters         []Chapter         // 기록할 챕터 (VOD 시작 기준)
+	NoArchive        bool              // 다운로드 기록을 확인하거나 남기지 않음
+	MinFreeSpace     int64             // 받는 중 여유 공간이 이보다 줄어들면 일시 정지 (0이면 감시하지 않음)
+	SkipSpaceCheck   bool              // 다운로드 전 여유 공간 확인 생략
+	StallTimeout     time.Duration     // 이 시간 동안 진행이 없으면 마지막 저장 위치부터 다시 받음 (0이면 감시하지 않음)
+	MaxRestarts      int               // 진행이 멈췄을 때 다시 받는 최대 횟수
+	Backend          string            // 다운로드 백엔드 이름 (비어 있거나 auto면 스트림 형식에 맞게 자동 선택)
+	SegmentWorkers   int               // HLS 세그먼트를 동시에 받는 수 (0이면 하나씩)
+	SkipLostSegments bool              // 끝내 받지 못한 세그먼트를 빠진 구간으로 기록하고 계속 받음 (false면 실패)
+
+	JobID    string           // 진행 이벤트를 구분하는 작업 ID (비어 있으면 파일명 사용)
+	Title    string           // 진행 표시에 사용할 제목
+	Reporter ProgressReporter // 진행 상황 출력기 (nil이면 터미널 한 줄 표시)
+}
+
+// DownloadResult 다운로드 결과
+type DownloadResult struct {
+	OutputFile     string         // 다운로드한 파일 (분할한 경우 삭제됨)
+	TranscodedFile string         // 트랜스코딩된 파일 (변환하지 않았으면 빈 문자열)
+	Parts          *SplitManifest // 분할된 파트 목록 (분할하지 않았으면 nil)
+	Duration       int            // 영상 길이 (초)
+	Skipped        bool           // 중복 파일이라 건너뛴 경우
+	Archived       *ArchiveRecord // 이미 받은 기록이 있어 건너뛴 경우 그 기록
+	Gaps           []SegmentGap   // 받지 못해 건너뛴 세그먼트 (출력 파일에서 빠진 구간)
+	GapReport      string         // 빠진 구간 보고서 파일 (<파일명>.gaps.json, 없으면 빈 문자열)
 }
@@ -0,0 +1,277 @@
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/config"
+)
+
+// 설치 기록 파일 이름 (dependent/<이름>/install.json)
+const installRecordName = "install.json"
+
+// 버전 확인 명령 제한 시간
+const versionTimeout = 10 * time.Second
+
+// 의존성 실행 파일 출처
+const (
+	SourceBundled = "bundled" // dependent 폴더에 설치한 파일
+	SourcePath    = "PATH"    // 직접 설치해 PATH에 있는 파일
+	SourceMissing = ""        // 찾지 못함
+)
+
+// InstallRecord 설치한 의존성 정보 (설치할 때 기록, 상태 확인과 복구에 사용)
+type InstallRecord struct {
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	URL          string    `json:"url"`
+	SHA256       string    `json:"sha256"`        // 받은 파일의 SHA-256
+	BinarySHA256 string    `json:"binary_sha256"` // 설치한 실행 파일의 SHA-256
+	InstalledAt  time.Time `json:"installed_at"`
+}
+
+// DependencyStatus 의존성 하나의 설치 상태
+type DependencyStatus struct {
+	Dependency Dependency
+	Source     string         // SourceBundled, SourcePath, SourceMissing
+	Path       string         // 사용할 실행 파일 경로
+	Record     *InstallRecord // 설치 기록 (직접 설치했거나 기록 이전에 설치했으면 nil)
+	Reported   string         // 실행 파일이 알려준 버전
+	Problem    error          // 설치가 망가졌으면 원인
+}
+
+// UpdateAvailable 설치한 버전이 목록에 고정된 버전과 다른지 여부 (PATH의 파일은 대상 아님)
+func (s DependencyStatus) UpdateAvailable() bool {
+	if s.Source != SourceBundled {
+		return false
+	}
+	return s.Record == nil || s.Record.Version != s.Dependency.Version
+}
+
+// FindDependency 현재 플랫폼의 의존성 목록에서 이름으로 찾음
+func FindDependency(name string) (Dependency, bool, error) {
+	deps, err := Dependencies()
+	if err != nil {
+		return Dependency{}, false, err
+	}
+	for _, dep := range deps {
+		if dep.Name == name {
+			return dep, true, nil
+		}
+	}
+	return Dependency{}, false, nil
+}
+
+// installDir 의존성 설치 폴더 (dependent/<이름>)
+func (d Dependency) installDir() string {
+	return filepath.Join(config.GetDependentDir(), d.DesiredName)
+}
+
+// LoadInstallRecord 설치 기록 읽기 (기록이 없으면 nil, nil)
+func LoadInstallRecord(dep Dependency) (*InstallRecord, error) {
+	data, err := os.ReadFile(filepath.Join(dep.installDir(), installRecordName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record InstallRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("설치 기록을 읽을 수 없습니다: %v", err)
+	}
+	return &record, nil
+}
+
+// writeInstallRecord dir에 설치 기록 저장
+func writeInstallRecord(dir string, record InstallRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, installRecordName), data, 0644)
+}
+
+// ReportedVersion 실행 파일에 버전을 물어 첫 줄에서 버전 번호를 꺼냄
+// (ffmpeg -version: "ffmpeg version 7.1.1-essentials ...", streamlink --version: "streamlink 7.1.2")
+func ReportedVersion(name, path string) (string, error) {
+	arg := "--version"
+	if name == "ffmpeg" {
+		arg = "-version"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, arg).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s %s 실행 실패: %v", path, arg, err)
+	}
+
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	fields := strings.Fields(line)
+	switch {
+	case len(fields) >= 3 && fields[1] == "version":
+		return fields[2], nil
+	case len(fields) >= 2 && fields[0] == name:
+		return fields[1], nil
+	case line != "":
+		return strings.TrimSpace(line), nil
+	}
+	return "", fmt.Errorf("%s %s 출력에서 버전을 찾을 수 없습니다", path, arg)
+}
+
+// Status 의존성 설치 상태 확인
+// 설치 폴더가 있으면 실행 파일, 설치 기록의 SHA-256, 버전 명령 순서로 확인하고
+// 없으면 PATH에서 찾아 버전만 확인
+func (d Dependency) Status() DependencyStatus {
+	status := DependencyStatus{Dependency: d}
+	bundled := config.BundledBinaryPath(d.DesiredName)
+
+	if _, err := os.Stat(d.installDir()); err == nil {
+		status.Source = SourceBundled
+		status.Path = bundled
+		status.Record, status.Problem = LoadInstallRecord(d)
+		if status.Problem != nil {
+			return status
+		}
+		if _, err := os.Stat(bundled); err != nil {
+			status.Problem = fmt.Errorf("설치 폴더에 실행 파일이 없습니다 (설치가 중간에 끊겼을 수 있음): %s", bundled)
+			return status
+		}
+		if status.Record != nil && status.Record.BinarySHA256 != "" {
+			digest, err := FileSHA256(bundled)
+			if err != nil {
+				status.Problem = err
+				return status
+			}
+			if !strings.EqualFold(digest, status.Record.BinarySHA256) {
+				status.Problem = fmt.Errorf("실행 파일이 설치한 뒤 바뀌었습니다 (SHA-256 불일치)")
+				return status
+			}
+		}
+	} else if path, err := exec.LookPath(config.ExecutableName(d.DesiredName)); err == nil {
+		status.Source = SourcePath
+		status.Path = path
+	} else {
+		return status
+	}
+
+	status.Reported, status.Problem = ReportedVersion(d.Name, status.Path)
+	return status
+}
+
+// recoverInterrupted 교체 도중 끊긴 설치 정리
+// 설치 폴더가 없고 이전 버전(.old)만 남아 있으면 되돌리고, 남은 임시 폴더(.new)는 지움
+func recoverInterrupted(dep Dependency) error {
+	finalDir := dep.installDir()
+	backupDir := finalDir + ".old"
+	if _, err := os.Stat(finalDir); os.IsNotExist(err) {
+		if _, err := os.Stat(backupDir); err == nil {
+			fmt.Printf("%s 이전 설치를 되돌리는 중: %s\n", dep.Name, backupDir)
+			if err := os.Rename(backupDir, finalDir); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.RemoveAll(finalDir + ".new"); err != nil {
+		return err
+	}
+	return os.RemoveAll(backupDir)
+}
+
+// swapInstall 준비한 stageDir을 설치 폴더와 바꾸는 함수
+// 바꾼 실행 파일이 버전 명령에 응답하지 않으면 새 파일을 지우고 이전 설치로 되돌림
+func swapInstall(dep Dependency, stageDir string) error {
+	finalDir := dep.installDir()
+	backupDir := finalDir + ".old"
+	if err := os.RemoveAll(backupDir); err != nil {
+		return err
+	}
+
+	hadPrevious := false
+	if _, err := os.Stat(finalDir); err == nil {
+		if err := os.Rename(finalDir, backupDir); err != nil {
+			return fmt.Errorf("기존 설치를 옮기지 못했습니다 (실행 중인지 확인하세요): %v", err)
+		}
+		hadPrevious = true
+	}
+
+	rollback := func(cause error) error {
+		if err := os.RemoveAll(finalDir); err != nil {
+			return fmt.Errorf("%v (새 파일 정리 실패: %v)", cause, err)
+		}
+		if hadPrevious {
+			if err := os.Rename(backupDir, finalDir); err != nil {
+				return fmt.Errorf("%v (이전 버전 복구 실패: %v)", cause, err)
+			}
+			return fmt.Errorf("%v, 이전 버전으로 되돌렸습니다", cause)
+		}
+		return cause
+	}
+
+	if err := os.Rename(stageDir, finalDir); err != nil {
+		return rollback(fmt.Errorf("설치 폴더 교체 실패: %v", err))
+	}
+	version, err := ReportedVersion(dep.Name, config.BundledBinaryPath(dep.DesiredName))
+	if err != nil {
+		return rollback(fmt.Errorf("새로 설치한 %s를 실행할 수 없습니다: %v", dep.Name, err))
+	}
+	fmt.Printf("%s 버전 확인: %s\n", dep.Name, version)
+
+	if err := os.RemoveAll(backupDir); err != nil {
+		fmt.Printf("이전 버전 폴더 삭제 실패: %v\n", err)
+	}
+	return nil
+}
+
+// UpdateDependency 설치한 의존성을 목록에 고정된 버전으로 바꿈
+// PATH에서 쓰는 파일은 건드리지 않으며, force면 같은 버전이어도 다시 설치
+func UpdateDependency(ctx context.Context, dep Dependency, force bool) (bool, error) {
+	if err := recoverInterrupted(dep); err != nil {
+		return false, err
+	}
+	status := dep.Status()
+	switch {
+	case status.Source == SourcePath:
+		fmt.Printf("%s는 PATH의 파일을 사용 중입니다 (%s). 직접 업데이트하세요\n", dep.Name, status.Path)
+		return false, nil
+	case status.Source == SourceBundled && !status.UpdateAvailable() && status.Problem == nil && !force:
+		fmt.Printf("%s는 이미 %s입니다\n", dep.Name, dep.Version)
+		return false, nil
+	}
+
+	if err := installDependency(ctx, dep, config.GetDataDir(), config.GetDependentDir()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RepairDependency 설치 상태를 확인해 망가졌으면 다시 설치
+// PATH에서 정상적으로 쓰고 있으면 그대로 두고, 어디에도 없으면 새로 설치
+func RepairDependency(ctx context.Context, dep Dependency) (bool, error) {
+	if err := recoverInterrupted(dep); err != nil {
+		return false, err
+	}
+	status := dep.Status()
+	if status.Source != SourceMissing && status.Problem == nil {
+		return false, nil
+	}
+	if status.Problem != nil {
+		fmt.Printf("%s 문제: %v\n", dep.Name, status.Problem)
+	}
+	if status.Source == SourcePath {
+		return false, errors.New("PATH의 파일이 망가졌습니다. 직접 다시 설치하거나 PATH에서 제거한 뒤 다시 시도하세요")
+	}
+
+	if err := installDependency(ctx, dep, config.GetDataDir(), config.GetDependentDir()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,281 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"chzzk-downloader/internal/utils"
+)
+
+const (
+	// 이 시간 동안 받은 데이터가 없으면 연결을 끊고 이어받기 (전체 시간 제한은 없음)
+	downloadIdleTimeout = 30 * time.Second
+	// 연결이 끊겼을 때 이어받기를 다시 시도하는 횟수
+	downloadRetries = 5
+	// 진행 상황 표시 간격
+	downloadProgressInterval = 500 * time.Millisecond
+)
+
+// permanentError 다시 시도해도 소용없는 오류 (404 등 HTTP 응답, 파일을 쓸 수 없음)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// DownloadFile URL에서 파일을 다운로드하는 함수
+// destPath.part에 받다가 끝나면 destPath로 바꾸며, .part가 남아 있으면 Range 요청으로 이어받음
+// 연결이 끊기거나 downloadIdleTimeout 동안 데이터가 없으면 downloadRetries번까지 이어받기를 다시 시도
+// ctx가 취소되면 받은 부분(.part)을 남기고 바로 중단
+// 받은 파일 전체의 SHA-256(16진수)을 함께 반환
+func DownloadFile(ctx context.Context, url, destPath string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", err
+	}
+	partPath := destPath + ".part"
+
+	fmt.Printf("다운로드 시작: %s\n", url)
+
+	var err error
+	for attempt := 0; attempt <= downloadRetries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("다운로드가 끊겨 이어받습니다 (%d/%d): %v\n", attempt, downloadRetries, err)
+			select {
+			case <-ctx.Done():
+				return "", interruptedDownload(ctx, partPath)
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+			}
+		}
+		err = downloadPart(ctx, url, partPath)
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", interruptedDownload(ctx, partPath)
+		}
+		return "", err
+	}
+
+	if err := os.Rename(partPath, destPath); err != nil {
+		return "", err
+	}
+	fmt.Printf("다운로드 완료: %s\n\n", destPath)
+
+	// 이어받은 경우에도 파일 전체를 확인해야 하므로 완성된 파일로 계산
+	return FileSHA256(destPath)
+}
+
+// interruptedDownload 취소되어 중단한 다운로드의 오류 (받은 부분은 다음에 이어받음)
+func interruptedDownload(ctx context.Context, partPath string) error {
+	return fmt.Errorf("다운로드를 중단했습니다 (받은 부분은 %s에 남아 다음에 이어받습니다): %w", partPath, ctx.Err())
+}
+
+// downloadPart partPath에 이미 받은 부분 뒤를 이어서 받는 함수
+func downloadPart(parent context.Context, url, partPath string) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	// 응답 대기와 본문 읽기 모두 마지막 데이터 이후 시간으로 제한
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	var idle atomic.Bool
+	timer := time.AfterFunc(downloadIdleTimeout, func() {
+		idle.Store(true)
+		cancel()
+	})
+	defer timer.Stop()
+	idleErr := func(err error) error {
+		if idle.Load() {
+			return fmt.Errorf("%s 동안 받은 데이터가 없습니다", downloadIdleTimeout)
+		}
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return idleErr(err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	var total int64 = -1
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// 요청한 위치가 아닌 곳부터 보내면 이어 붙일 수 없으므로 처음부터 받음
+			os.Remove(partPath)
+			return fmt.Errorf("서버가 요청과 다른 범위를 보냈습니다 (%s)", resp.Header.Get("Content-Range"))
+		}
+		fmt.Printf("이어받기: %s부터\n", utils.FormatBytes(offset))
+		flags |= os.O_APPEND
+		total = size
+	case http.StatusOK:
+		// Range를 지원하지 않는 서버는 처음부터 보냄
+		offset = 0
+		flags |= os.O_TRUNC
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 이미 끝까지 받은 경우 (bytes */전체 크기)
+		if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size == offset {
+			return nil
+		}
+		os.Remove(partPath)
+		return fmt.Errorf("받아 둔 파일이 서버의 파일과 맞지 않아 처음부터 받습니다")
+	default:
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return &permanentError{err: fmt.Errorf("다운로드 실패: HTTP %s", resp.Status)}
+		}
+		return fmt.Errorf("다운로드 실패: HTTP %s", resp.Status)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+
+	progress := newDownloadProgress(offset, total)
+	buf := make([]byte, 64*1024)
+	written := offset
+	var copyErr error
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			timer.Reset(downloadIdleTimeout)
+			if _, err := out.Write(buf[:n]); err != nil {
+				copyErr = err
+				break
+			}
+			written += int64(n)
+			progress.update(written)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			copyErr = idleErr(err)
+			break
+		}
+	}
+	progress.finish(written)
+
+	if err := out.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+	if total >= 0 && written != total {
+		return fmt.Errorf("파일을 끝까지 받지 못했습니다 (%s / %s)", utils.FormatBytes(written), utils.FormatBytes(total))
+	}
+	return nil
+}
+
+// parseContentRange "bytes 100-199/1000" 또는 "bytes */1000"에서 시작 위치와 전체 크기를 읽음
+// 전체 크기를 알 수 없으면(*) size는 -1
+func parseContentRange(value string) (start, size int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(value), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rangePart, sizePart, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size = -1
+	if sizePart != "*" {
+		parsed, err := strconv.ParseInt(sizePart, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = parsed
+	}
+	if rangePart == "*" {
+		return 0, size, true
+	}
+
+	startPart, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+// downloadProgress 받은 크기, 속도, 남은 시간을 한 줄로 표시
+type downloadProgress struct {
+	started   time.Time
+	startSize int64 // 이번 연결을 시작할 때 이미 받은 크기 (속도 계산에서 제외)
+	total     int64 // 전체 크기 (모르면 -1)
+	lastPrint time.Time
+}
+
+func newDownloadProgress(startSize, total int64) *downloadProgress {
+	return &downloadProgress{started: time.Now(), startSize: startSize, total: total}
+}
+
+// update 표시 간격이 지났으면 진행 상황 출력
+func (p *downloadProgress) update(written int64) {
+	if time.Since(p.lastPrint) < downloadProgressInterval {
+		return
+	}
+	p.lastPrint = time.Now()
+	p.print(written)
+}
+
+// finish 마지막 진행 상황을 출력하고 줄을 바꿈
+func (p *downloadProgress) finish(written int64) {
+	p.print(written)
+	fmt.Println()
+}
+
+func (p *downloadProgress) print(written int64) {
+	var speed float64
+	if elapsed := time.Since(p.started).Seconds(); elapsed > 0 {
+		speed = float64(written-p.startSize) / elapsed
+	}
+
+	line := fmt.Sprintf("받는 중: %s", utils.FormatBytes(written))
+	if p.total > 0 {
+		line += fmt.Sprintf(" / %s (%.1f%%)", utils.FormatBytes(p.total), float64(written)*100/float64(p.total))
+	}
+	line += fmt.Sprintf(", %s/s", utils.FormatBytes(int64(speed)))
+	if p.total > 0 && speed > 0 && written < p.total {
+		line += fmt.Sprintf(", 남은 시간 %s", utils.SecondsToHms(int(float64(p.total-written)/speed)))
+	}
+	fmt.Printf("\r%-80s", line)
+}
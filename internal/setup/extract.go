@@ -0,0 +1,238 @@
+package setup
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 압축 해제 결과 전체 크기 상한 (압축 폭탄 방지, 가장 큰 의존성도 수백 MiB 수준)
+const maxExtractSize int64 = 4 << 30
+
+// UnsafeEntryError 압축 파일 항목이 안전하지 않아 압축을 풀지 않았을 때의 오류
+type UnsafeEntryError struct {
+	Name   string
+	Reason string
+}
+
+func (e *UnsafeEntryError) Error() string {
+	return fmt.Sprintf("압축 파일의 %q 항목을 풀 수 없습니다: %s", e.Name, e.Reason)
+}
+
+// extractor 압축 파일 항목을 root 아래에만 쓰도록 검사하며 푸는 도구
+// 항목 이름과 링크 대상은 모두 root 안쪽이어야 하고, 푼 크기 합계는 limit을 넘을 수 없음
+type extractor struct {
+	root    string
+	limit   int64
+	written int64
+	topDirs []string
+}
+
+// newExtractor root를 비우고 다시 만든 뒤 extractor 생성
+func newExtractor(root string) (*extractor, error) {
+	if err := os.RemoveAll(root); err != nil {
+		return nil, fmt.Errorf("임시 추출 디렉토리 정리 실패: %v", err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("임시 추출 디렉토리 생성 실패: %v", err)
+	}
+	return &extractor{root: root, limit: maxExtractSize}, nil
+}
+
+// entryName 압축 파일 항목 이름을 정리하고 root 안쪽의 상대 경로인지 확인
+// 절대 경로, 드라이브 문자, ".."로 벗어나는 이름은 거부
+func (x *extractor) entryName(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./"))
+	if cleaned == "." {
+		return "", nil
+	}
+	// 드라이브 문자("C:")는 Windows가 아닌 곳에서는 일반 이름이지만 어디서든 거부
+	if !filepath.IsLocal(filepath.FromSlash(cleaned)) || hasDriveLetter(cleaned) {
+		return "", &UnsafeEntryError{Name: name, Reason: "압축 해제 경로 밖을 가리킵니다"}
+	}
+
+	// 최상위 디렉토리 기록 (설치할 실행 파일 위치 추정에 사용)
+	if dir, _, found := strings.Cut(cleaned, "/"); found && !contains(x.topDirs, dir) {
+		x.topDirs = append(x.topDirs, dir)
+	}
+	return cleaned, nil
+}
+
+// hasDriveLetter "C:"처럼 드라이브 문자로 시작하는 이름인지 확인
+func hasDriveLetter(name string) bool {
+	if len(name) < 2 || name[1] != ':' {
+		return false
+	}
+	c := name[0] | 0x20
+	return c >= 'a' && c <= 'z'
+}
+
+// target 항목 이름에 해당하는 실제 경로
+// 경로 중간에 이미 만든 심볼릭 링크가 있으면 링크를 따라 밖으로 쓸 수 있으므로 거부
+func (x *extractor) target(name string) (string, error) {
+	cleaned, err := x.entryName(name)
+	if err != nil {
+		return "", err
+	}
+	if cleaned == "" {
+		return x.root, nil
+	}
+
+	parts := strings.Split(cleaned, "/")
+	current := x.root
+	for _, part := range parts[:len(parts)-1] {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", &UnsafeEntryError{Name: name, Reason: "심볼릭 링크를 거쳐 쓰려고 합니다"}
+		}
+	}
+	return filepath.Join(x.root, filepath.FromSlash(cleaned)), nil
+}
+
+// dir 디렉토리 항목 생성
+func (x *extractor) dir(name string) error {
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %v", err)
+	}
+	return nil
+}
+
+// file 일반 파일 항목 생성 (권한은 rwx 비트만 사용)
+// 같은 이름의 링크가 이미 있으면 링크 대상에 쓰지 않도록 먼저 지움
+func (x *extractor) file(name string, mode os.FileMode, r io.Reader) error {
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %v", err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	outFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+
+	// 남은 허용량보다 1바이트 더 읽어 초과 여부 확인
+	remaining := x.limit - x.written
+	n, err := io.Copy(outFile, io.LimitReader(r, remaining+1))
+	x.written += n
+	if closeErr := outFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > remaining {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("압축을 푼 크기가 %d MiB를 넘습니다", x.limit>>20)}
+	}
+	return nil
+}
+
+// symlink 심볼릭 링크 항목 생성
+// 링크 대상은 링크가 있는 디렉토리 기준 상대 경로이고 root 안쪽이어야 함
+func (x *extractor) symlink(name, linkTarget string) error {
+	cleaned, err := x.entryName(name)
+	if err != nil {
+		return err
+	}
+	if cleaned == "" || linkTarget == "" || path.IsAbs(linkTarget) || filepath.IsAbs(linkTarget) || filepath.VolumeName(linkTarget) != "" {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("링크 대상 %q이(가) 안전하지 않습니다", linkTarget)}
+	}
+	if hasDriveLetter(linkTarget) {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("링크 대상 %q이(가) 안전하지 않습니다", linkTarget)}
+	}
+	resolved := path.Join(path.Dir(cleaned), strings.ReplaceAll(linkTarget, "\\", "/"))
+	if !filepath.IsLocal(filepath.FromSlash(resolved)) {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("링크 대상 %q이(가) 압축 해제 경로 밖을 가리킵니다", linkTarget)}
+	}
+
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %v", err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(filepath.FromSlash(linkTarget), target)
+}
+
+// hardlink 하드 링크 항목 생성 (tar의 하드 링크 대상은 압축 파일 최상위 기준 경로)
+func (x *extractor) hardlink(name, linkTarget string) error {
+	source, err := x.target(linkTarget)
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(source)
+	if err != nil {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("링크 대상 %q이(가) 없습니다", linkTarget)}
+	}
+	if !info.Mode().IsRegular() {
+		return &UnsafeEntryError{Name: name, Reason: fmt.Sprintf("링크 대상 %q이(가) 일반 파일이 아닙니다", linkTarget)}
+	}
+
+	target, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %v", err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Link(source, target)
+}
+
+// extractTar tar 스트림의 항목을 모두 해제
+func extractTar(x *extractor, r io.Reader) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(header.Name)
+		case tar.TypeReg:
+			err = x.file(header.Name, os.FileMode(header.Mode), reader)
+		case tar.TypeSymlink:
+			err = x.symlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = x.hardlink(header.Name, header.Linkname)
+		case tar.TypeXGlobalHeader:
+			// pax 전역 헤더는 파일이 아님
+		default:
+			err = &UnsafeEntryError{Name: header.Name, Reason: "지원하지 않는 항목 종류입니다"}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
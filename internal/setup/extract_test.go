@@ -0,0 +1,362 @@
+package setup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// archiveEntry 테스트용 압축 파일 항목
+type archiveEntry struct {
+	name string
+	body string
+	link string // 심볼릭 링크 또는 하드 링크 대상
+	typ  byte   // tar.TypeReg, tar.TypeDir, tar.TypeSymlink, tar.TypeLink
+}
+
+func entryFile(name, body string) archiveEntry {
+	return archiveEntry{name: name, body: body, typ: tar.TypeReg}
+}
+func entryDir(name string) archiveEntry { return archiveEntry{name: name, typ: tar.TypeDir} }
+func entrySymlink(name, link string) archiveEntry {
+	return archiveEntry{name: name, link: link, typ: tar.TypeSymlink}
+}
+func entryHardlink(name, link string) archiveEntry {
+	return archiveEntry{name: name, link: link, typ: tar.TypeLink}
+}
+
+func buildTar(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typ, Linkname: e.link, Mode: 0755}
+		if e.typ == tar.TypeReg {
+			header.Size = int64(len(e.body))
+			header.Mode = 0644
+		}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if e.typ == tar.TypeReg {
+			if _, err := w.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		body := e.body
+		switch e.typ {
+		case tar.TypeDir:
+			header.SetMode(fs.ModeDir | 0755)
+		case tar.TypeSymlink:
+			// zip의 심볼릭 링크는 내용이 링크 대상
+			header.SetMode(fs.ModeSymlink | 0777)
+			body = e.link
+		case tar.TypeLink:
+			t.Fatal("zip has no hard links")
+		default:
+			header.SetMode(0644)
+		}
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func extractTarBytes(x *extractor, data []byte) error {
+	return extractTar(x, bytes.NewReader(data))
+}
+
+func extractZipBytes(x *extractor, data []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		if err := extractZipEntry(x, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot root 밖의 모든 경로와 파일 내용
+func snapshot(t *testing.T, base, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(base, path)
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, _ := os.Readlink(path)
+			files[rel] = "-> " + link
+		case d.Type().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[rel] = string(data)
+		default:
+			files[rel] = "dir"
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestExtractRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		tarOnly bool
+		wantErr bool
+		limit   int64 // 0이면 기본 상한
+	}{
+		{name: "parent traversal", entries: []archiveEntry{entryFile("../evil", "x")}, wantErr: true},
+		{name: "nested parent traversal", entries: []archiveEntry{entryFile("pkg/../../evil", "x")}, wantErr: true},
+		{name: "deep traversal", entries: []archiveEntry{entryFile("../../../../../../tmp/evil", "x")}, wantErr: true},
+		{name: "backslash traversal", entries: []archiveEntry{entryFile(`..\evil`, "x")}, wantErr: true},
+		{name: "absolute path", entries: []archiveEntry{entryFile("/tmp/evil", "x")}, wantErr: true},
+		{name: "backslash absolute path", entries: []archiveEntry{entryFile(`\tmp\evil`, "x")}, wantErr: true},
+		{name: "UNC path", entries: []archiveEntry{entryFile(`\\server\share\evil`, "x")}, wantErr: true},
+		{name: "drive letter", entries: []archiveEntry{entryFile("C:/evil", "x")}, wantErr: true},
+		{name: "drive letter backslash", entries: []archiveEntry{entryFile(`c:\evil`, "x")}, wantErr: true},
+		{name: "drive relative", entries: []archiveEntry{entryFile("D:evil", "x")}, wantErr: true},
+		{name: "directory traversal", entries: []archiveEntry{entryDir("../evil/")}, wantErr: true},
+		{name: "symlink to parent", entries: []archiveEntry{entrySymlink("link", "../outside")}, wantErr: true},
+		{name: "symlink to absolute path", entries: []archiveEntry{entrySymlink("link", "/etc")}, wantErr: true},
+		{name: "symlink to drive", entries: []archiveEntry{entrySymlink("link", "C:/Windows")}, wantErr: true},
+		{name: "nested symlink escaping", entries: []archiveEntry{entrySymlink("pkg/bin/link", "../../../outside")}, wantErr: true},
+		{name: "empty symlink target", entries: []archiveEntry{entrySymlink("link", "")}, wantErr: true},
+		{
+			name:    "write through extracted symlink",
+			entries: []archiveEntry{entryDir("sub/"), entrySymlink("link", "sub"), entryFile("link/evil", "x")},
+			wantErr: true,
+		},
+		{
+			name:    "symlink through extracted symlink",
+			entries: []archiveEntry{entryDir("sub/"), entrySymlink("link", "sub"), entrySymlink("link/escape", "../../outside")},
+			wantErr: true,
+		},
+		{
+			name:    "hardlink outside root",
+			entries: []archiveEntry{entryHardlink("hl", "../outside/secret")},
+			tarOnly: true,
+			wantErr: true,
+		},
+		{
+			name:    "hardlink to symlink",
+			entries: []archiveEntry{entryFile("f", "x"), entrySymlink("s", "f"), entryHardlink("h", "s")},
+			tarOnly: true,
+			wantErr: true,
+		},
+		{
+			name:    "hardlink to missing file",
+			entries: []archiveEntry{entryHardlink("h", "missing")},
+			tarOnly: true,
+			wantErr: true,
+		},
+		{
+			name:    "single file over size limit",
+			entries: []archiveEntry{entryFile("big", strings.Repeat("x", 2048))},
+			limit:   1024,
+			wantErr: true,
+		},
+		{
+			name:    "total over size limit",
+			entries: []archiveEntry{entryFile("a", strings.Repeat("x", 600)), entryFile("b", strings.Repeat("x", 600))},
+			limit:   1024,
+			wantErr: true,
+		},
+		{
+			name:    "exactly at size limit",
+			entries: []archiveEntry{entryFile("a", strings.Repeat("x", 512)), entryFile("b", strings.Repeat("x", 512))},
+			limit:   1024,
+		},
+		{
+			name: "safe archive",
+			entries: []archiveEntry{
+				entryDir("./pkg/"),
+				entryFile("pkg/bin/tool", "binary"),
+				entrySymlink("pkg/bin/alias", "tool"),
+				entrySymlink("pkg/lib", "bin"),
+				entryFile("pkg/bin/../README", "readme"),
+			},
+		},
+		{
+			name:    "safe hardlink",
+			entries: []archiveEntry{entryFile("pkg/tool", "binary"), entryHardlink("pkg/tool2", "pkg/tool")},
+			tarOnly: true,
+		},
+	}
+
+	formats := []struct {
+		name    string
+		build   func(*testing.T, []archiveEntry) []byte
+		extract func(*extractor, []byte) error
+	}{
+		{"tar", buildTar, extractTarBytes},
+		{"zip", buildZip, extractZipBytes},
+	}
+
+	for _, format := range formats {
+		for _, tt := range tests {
+			if tt.tarOnly && format.name != "tar" {
+				continue
+			}
+			t.Run(format.name+"/"+tt.name, func(t *testing.T) {
+				if runtime.GOOS == "windows" && slices.ContainsFunc(tt.entries, func(e archiveEntry) bool { return e.typ == tar.TypeSymlink }) {
+					t.Skip("creating symlinks needs extra privileges on Windows")
+				}
+
+				// base/outside/secret은 링크로 덮어쓰거나 읽으면 안 되는 파일
+				base := t.TempDir()
+				root := filepath.Join(base, "extract", "root")
+				if err := os.MkdirAll(filepath.Join(base, "outside"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(base, "outside", "secret"), []byte("secret"), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				x, err := newExtractor(root)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.limit > 0 {
+					x.limit = tt.limit
+				}
+				before := snapshot(t, base, root)
+
+				err = format.extract(x, format.build(t, tt.entries))
+				if tt.wantErr {
+					var unsafe *UnsafeEntryError
+					if !errors.As(err, &unsafe) {
+						t.Errorf("error = %v, want *UnsafeEntryError", err)
+					}
+				} else if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+
+				after := snapshot(t, base, root)
+				for path, content := range after {
+					if before[path] != content {
+						t.Errorf("wrote outside root: %s = %q", path, content)
+					}
+				}
+				for path := range before {
+					if _, ok := after[path]; !ok {
+						t.Errorf("removed outside root: %s", path)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestExtractSafeArchiveContents(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on Windows")
+	}
+	entries := []archiveEntry{
+		entryFile("pkg/bin/tool", "binary"),
+		entrySymlink("pkg/bin/alias", "tool"),
+		entryFile("pkg/bin/tool", "replaced"),
+	}
+	for name, extract := range map[string]func(*extractor, []byte) error{"tar": extractTarBytes, "zip": extractZipBytes} {
+		t.Run(name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "root")
+			x, err := newExtractor(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var data []byte
+			if name == "tar" {
+				data = buildTar(t, entries)
+			} else {
+				data = buildZip(t, entries)
+			}
+			if err := extract(x, data); err != nil {
+				t.Fatal(err)
+			}
+
+			// 같은 이름이 다시 나오면 덮어씀, 링크는 그대로 따라감
+			got, err := os.ReadFile(filepath.Join(root, "pkg", "bin", "alias"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "replaced" {
+				t.Errorf("alias = %q, want %q", got, "replaced")
+			}
+			if !slices.Equal(x.topDirs, []string{"pkg"}) {
+				t.Errorf("topDirs = %v, want [pkg]", x.topDirs)
+			}
+		})
+	}
+}
+
+func TestExtractDoesNotWriteThroughReplacedLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on Windows")
+	}
+	// 링크와 같은 이름의 파일 항목은 링크 대상이 아니라 링크 자리에 새 파일로 써야 함
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	x, err := newExtractor(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buildTar(t, []archiveEntry{entryFile("target", "original"), entrySymlink("link", "target"), entryFile("link", "new")})
+	if err := extractTarBytes(x, data); err != nil {
+		t.Fatal(err)
+	}
+
+	target, _ := os.ReadFile(filepath.Join(root, "target"))
+	if string(target) != "original" {
+		t.Errorf("target = %q, want %q (written through link)", target, "original")
+	}
+	info, err := os.Lstat(filepath.Join(root, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf("link is %v, want a regular file", info.Mode())
+	}
+}
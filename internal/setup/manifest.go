@@ -0,0 +1,162 @@
+package setup
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"chzzk-downloader/internal/config"
+)
+
+// 의존성 압축 형식
+const (
+	FormatZip   = "zip"
+	FormatTarXz = "tar.xz"
+)
+
+// Platform 현재 운영체제/아키텍처 (예: linux/amd64)
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// 운영체제/아키텍처별 다운로드 의존성 목록
+// 목록에 있는 의존성은 필수이며, 목록에 없는 streamlink는 PATH에 있을 때만 사용
+// (streamlink가 없으면 내장 HLS 백엔드로 받음)
+// 모든 항목은 버전을 고정한 주소와 그 파일의 SHA-256을 함께 등록해야 자동 설치됨
+// (버전을 올릴 때는 배포 페이지의 파일을 받아 SHA-256을 직접 확인한 뒤 URL과 함께 바꿈)
+// 아래 항목의 SHA-256은 아직 확인하지 않아 비어 있음 (채우기 전까지는 자동 설치하지 않고 UnpinnedError를 반환)
+var manifests = map[string][]Dependency{
+	"windows/amd64": {
+		{
+			Name:        "ffmpeg",
+			Version:     "7.1.1",
+			URL:         "https://github.com/GyanD/codexffmpeg/releases/download/7.1.1/ffmpeg-7.1.1-essentials_build.zip",
+			SHA256:      "",
+			DesiredName: "ffmpeg",
+			Format:      FormatZip,
+			Binary:      "bin/ffmpeg.exe",
+		},
+		{
+			Name:        "streamlink",
+			Version:     "7.1.2-2",
+			URL:         "https://github.com/streamlink/windows-builds/releases/download/7.1.2-2/streamlink-7.1.2-2-py312-x86_64.zip",
+			SHA256:      "",
+			DesiredName: "streamlink",
+			Format:      FormatZip,
+			Binary:      "bin/streamlink.exe",
+		},
+	},
+	"linux/amd64": {
+		{
+			Name:        "ffmpeg",
+			Version:     "7.0.2",
+			URL:         "https://johnvansickle.com/ffmpeg/old-releases/ffmpeg-7.0.2-amd64-static.tar.xz",
+			SHA256:      "",
+			DesiredName: "ffmpeg",
+			Format:      FormatTarXz,
+			Binary:      "ffmpeg",
+		},
+		{
+			Name:         "streamlink",
+			Version:      "7.1.2-1",
+			URL:          "https://github.com/streamlink/streamlink-appimage/releases/download/7.1.2-1/streamlink-7.1.2-1-cp312-cp312-manylinux_2_28_x86_64.AppImage",
+			SHA256:       "",
+			DesiredName:  "streamlink",
+			IsExecutable: true,
+		},
+	},
+	"linux/arm64": {
+		{
+			Name:        "ffmpeg",
+			Version:     "7.0.2",
+			URL:         "https://johnvansickle.com/ffmpeg/old-releases/ffmpeg-7.0.2-arm64-static.tar.xz",
+			SHA256:      "",
+			DesiredName: "ffmpeg",
+			Format:      FormatTarXz,
+			Binary:      "ffmpeg",
+		},
+		{
+			Name:         "streamlink",
+			Version:      "7.1.2-1",
+			URL:          "https://github.com/streamlink/streamlink-appimage/releases/download/7.1.2-1/streamlink-7.1.2-1-cp312-cp312-manylinux_2_28_aarch64.AppImage",
+			SHA256:       "",
+			DesiredName:  "streamlink",
+			IsExecutable: true,
+		},
+	},
+	// macOS는 streamlink 단일 실행 파일 배포가 없으므로 ffmpeg만 설치 (streamlink는 brew/pip로 설치하면 사용)
+	"darwin/amd64": {
+		{
+			Name:        "ffmpeg",
+			Version:     "7.1.1",
+			URL:         "https://evermeet.cx/ffmpeg/ffmpeg-7.1.1.zip",
+			SHA256:      "",
+			DesiredName: "ffmpeg",
+			Format:      FormatZip,
+			Binary:      "ffmpeg",
+		},
+	},
+	"darwin/arm64": {
+		{
+			// Apple Silicon에서는 Rosetta 2로 실행
+			Name:        "ffmpeg",
+			Version:     "7.1.1",
+			URL:         "https://evermeet.cx/ffmpeg/ffmpeg-7.1.1.zip",
+			SHA256:      "",
+			DesiredName: "ffmpeg",
+			Format:      FormatZip,
+			Binary:      "ffmpeg",
+		},
+	},
+}
+
+// Dependencies 현재 플랫폼의 의존성 목록 (지원하지 않는 플랫폼이면 nil)
+func Dependencies() ([]Dependency, error) {
+	return DependenciesFor(Platform())
+}
+
+// DependenciesFor platform의 의존성 목록
+// 기본 목록에 dependencies.json(미러, 항목 덮어쓰기)을 적용하고,
+// 오프라인 번들을 사용 중이면 번들 안의 파일로 바꿈
+// dependencies.json은 설정 폴더에서 먼저 찾고, 없으면 실행 파일 옆(관리자가 함께 배포한 파일)에서 찾음
+func DependenciesFor(platform string) ([]Dependency, error) {
+	deps := append([]Dependency(nil), manifests[platform]...)
+
+	var override *manifestOverride
+	var baseDir string
+	var err error
+	for _, dir := range []string{config.GetBaseDir(), config.GetExecutableDir()} {
+		if override, err = loadManifestOverride(filepath.Join(dir, config.DependencyManifestFile)); err != nil {
+			return nil, err
+		}
+		if override != nil {
+			baseDir = dir
+			break
+		}
+	}
+	if override != nil {
+		if deps, err = override.apply(deps, platform, baseDir); err != nil {
+			return nil, err
+		}
+	}
+	if bundleDir != "" {
+		if deps, err = applyBundle(deps, platform); err != nil {
+			return nil, err
+		}
+	}
+	if len(deps) == 0 {
+		return nil, nil
+	}
+	return deps, nil
+}
+
+// Path 의존성 실행 파일 경로 (설치한 파일, PATH 순서로 찾음)
+func (d Dependency) Path() string {
+	switch d.Name {
+	case "ffmpeg":
+		return config.GetFFmpeg()
+	case "streamlink":
+		return config.GetStreamlink()
+	default:
+		return config.BundledBinaryPath(d.DesiredName)
+	}
+}
@@ -0,0 +1,28 @@
+package setup
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestManifestsArePinned 기본 목록의 모든 항목에 버전을 고정한 주소와 SHA-256이 있는지 확인
+// 하나라도 비어 있으면 그 플랫폼에서는 첫 실행 때 자동 설치가 UnpinnedError로 실패함
+func TestManifestsArePinned(t *testing.T) {
+	platforms := make([]string, 0, len(manifests))
+	for platform := range manifests {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	for _, platform := range platforms {
+		for _, dep := range manifests[platform] {
+			if err := checkPinned(dep); err != nil {
+				t.Errorf("%s: %v (URL: %s)", platform, err, dep.URL)
+			}
+			if dep.Version == "" || !strings.Contains(dep.URL, dep.Version) {
+				t.Errorf("%s: %s URL에 고정한 버전 %q가 없습니다: %s", platform, dep.Name, dep.Version, dep.URL)
+			}
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package setup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"chzzk-downloader/internal/config"
+)
+
+// manifestOverride 의존성 목록 덮어쓰기 설정 (설정 폴더, 실행 파일 옆 또는 오프라인 번들 안의 dependencies.json)
+//
+//	{
+//	  "mirror": "https://mirror.example/chzzk-deps/",
+//	  "platforms": {
+//	    "linux/amd64": [{"name": "ffmpeg", "url": "ffmpeg.tar.xz", "sha256": "..."}]
+//	  }
+//	}
+//
+// mirror는 URL을 지정하지 않은 항목의 파일 이름 앞에 붙이며 로컬 폴더도 가능
+// 상대 경로 URL과 mirror는 설정 파일이 있는 폴더 기준 로컬 경로
+type manifestOverride struct {
+	Mirror    string                       `json:"mirror,omitempty"`
+	Platforms map[string][]dependencyEntry `json:"platforms,omitempty"`
+}
+
+// dependencyEntry 덮어쓸 의존성 항목 (비어 있는 값은 기본 목록의 값을 유지)
+type dependencyEntry struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	URL        string `json:"url,omitempty"`
+	SHA256     string `json:"sha256,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Binary     string `json:"binary,omitempty"`
+	Executable bool   `json:"executable,omitempty"` // 압축 파일이 아닌 실행 파일 하나
+}
+
+// 오프라인 번들 폴더 (UseBundle로 지정하면 모든 의존성을 이 폴더에서 설치)
+var bundleDir string
+
+// loadManifestOverride 덮어쓰기 설정 읽기 (파일이 없으면 nil, nil)
+func loadManifestOverride(file string) (*manifestOverride, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var override manifestOverride
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, fmt.Errorf("%s 파싱 실패: %v", file, err)
+	}
+	return &override, nil
+}
+
+// apply platform의 의존성 목록에 덮어쓰기 설정 적용
+// 목록에 없는 이름은 새 의존성으로 추가되고, baseDir는 상대 경로의 기준 폴더
+func (o *manifestOverride) apply(deps []Dependency, platform, baseDir string) ([]Dependency, error) {
+	explicit := make(map[string]bool)
+	for _, entry := range o.Platforms[platform] {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("%s 의존성 항목에 name이 없습니다", platform)
+		}
+
+		index := -1
+		for i := range deps {
+			if deps[i].Name == entry.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			deps = append(deps, Dependency{Name: entry.Name, DesiredName: entry.Name, Format: FormatZip})
+			index = len(deps) - 1
+		}
+
+		dep := &deps[index]
+		if entry.Version != "" {
+			dep.Version = entry.Version
+		}
+		if entry.URL != "" {
+			dep.URL = resolveSource(entry.URL, baseDir)
+			explicit[dep.Name] = true
+		}
+		if entry.SHA256 != "" {
+			dep.SHA256 = entry.SHA256
+		}
+		if entry.Format != "" {
+			dep.Format = entry.Format
+		}
+		if entry.Binary != "" {
+			dep.Binary = entry.Binary
+		}
+		if entry.Executable {
+			dep.IsExecutable = true
+		}
+	}
+
+	if o.Mirror != "" {
+		mirror := resolveSource(o.Mirror, baseDir)
+		for i := range deps {
+			if !explicit[deps[i].Name] && deps[i].URL != "" {
+				deps[i].URL = mirrorSource(mirror, deps[i].URL)
+			}
+		}
+	}
+	return deps, nil
+}
+
+// isLocalSource 주소가 로컬 파일 경로인지 (file:// 또는 스킴 없음)
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "file://") || !strings.Contains(source, "://")
+}
+
+// localPath file:// 주소를 파일 경로로 변환
+func localPath(source string) string {
+	p, ok := strings.CutPrefix(source, "file://")
+	if !ok {
+		return source
+	}
+	// file:///C:/deps -> C:/deps
+	if runtime.GOOS == "windows" && len(p) > 2 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+// resolveSource 상대 경로를 baseDir 기준 경로로 바꿈 (원격 주소는 그대로)
+func resolveSource(source, baseDir string) string {
+	if !isLocalSource(source) {
+		return source
+	}
+	p := localPath(source)
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(baseDir, p)
+	}
+	return p
+}
+
+// mirrorSource 원래 주소의 파일 이름을 미러 주소(또는 폴더) 뒤에 붙임
+func mirrorSource(mirror, source string) string {
+	name := path.Base(filepath.ToSlash(source))
+	if isLocalSource(mirror) {
+		return filepath.Join(mirror, name)
+	}
+	return strings.TrimSuffix(mirror, "/") + "/" + name
+}
+
+// fetchFile 의존성 파일을 dest로 가져오고 SHA-256(16진수)을 반환
+// 로컬 경로면 복사하고, 원격 주소면 이어받기를 지원하는 DownloadFile로 받음 (ctx가 취소되면 중단)
+func fetchFile(ctx context.Context, source, dest string) (string, error) {
+	if !isLocalSource(source) {
+		return DownloadFile(ctx, source, dest)
+	}
+
+	src := localPath(source)
+	fmt.Printf("로컬 파일 복사: %s\n", src)
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("파일이 없습니다: %s", src)
+		}
+		return "", err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hash), in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// UseBundle 오프라인 번들(폴더, .zip 또는 .tar.xz)에서 의존성을 설치하도록 설정
+// 번들에는 의존성 목록 URL의 파일 이름 그대로 파일이 들어 있어야 하며, dependencies.json이 있으면 함께 적용
+// 반환한 함수로 압축을 푼 임시 폴더를 정리하고 설정을 되돌림
+func UseBundle(bundlePath string) (func(), error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("오프라인 번들을 열 수 없습니다: %v", err)
+	}
+
+	dir := bundlePath
+	cleanup := func() {}
+	if !info.IsDir() {
+		extractDir := filepath.Join(config.GetDataDir(), "temp_bundle")
+		switch {
+		case strings.HasSuffix(bundlePath, ".tar.xz"):
+			_, err = ExtractTarXz(bundlePath, extractDir)
+		case strings.HasSuffix(bundlePath, ".zip"):
+			_, err = ExtractZip(bundlePath, extractDir)
+		default:
+			return nil, fmt.Errorf("오프라인 번들은 폴더, .zip, .tar.xz 중 하나여야 합니다: %s", bundlePath)
+		}
+		if err != nil {
+			os.RemoveAll(extractDir + "_temp")
+			return nil, fmt.Errorf("오프라인 번들 압축 해제 실패: %v", err)
+		}
+		dir = bundleRoot(extractDir + "_temp")
+		cleanup = func() { os.RemoveAll(extractDir + "_temp") }
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	bundleDir = absDir
+	return func() {
+		bundleDir = ""
+		cleanup()
+	}, nil
+}
+
+// bundleRoot 압축을 푼 폴더에 하위 폴더 하나만 있으면 그 폴더를 번들로 사용
+func bundleRoot(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+// applyBundle 번들 폴더의 dependencies.json을 적용하고 원격 주소를 번들 안의 파일로 바꿈
+func applyBundle(deps []Dependency, platform string) ([]Dependency, error) {
+	override, err := loadManifestOverride(filepath.Join(bundleDir, config.DependencyManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	if override == nil {
+		override = &manifestOverride{}
+	}
+	override.Mirror = bundleDir
+
+	// 번들 설정에서 원격 주소를 지정해도 번들 안의 파일을 사용
+	deps, err = override.apply(deps, platform, bundleDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deps {
+		if !isLocalSource(deps[i].URL) {
+			deps[i].URL = mirrorSource(bundleDir, deps[i].URL)
+		}
+	}
+	return deps, nil
+}
+
+// CreateBundle 지정한 플랫폼들의 의존성을 받아 오프라인 번들 폴더를 만듦
+// 파일마다 SHA-256을 확인하며, 번들을 만들 때 사용한 목록을 dependencies.json으로 함께 저장
+// ctx가 취소되면 받던 파일을 남기고 중단 (다시 만들면 이어받음)
+func CreateBundle(ctx context.Context, dir string, platforms []string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	override := manifestOverride{Platforms: make(map[string][]dependencyEntry)}
+	fetched := make(map[string]bool)
+	for _, platform := range platforms {
+		deps, err := DependenciesFor(platform)
+		if err != nil {
+			return err
+		}
+		if deps == nil {
+			return fmt.Errorf("의존성 목록이 없는 플랫폼입니다: %s", platform)
+		}
+
+		for _, dep := range deps {
+			if err := checkPinned(dep); err != nil {
+				return err
+			}
+			name := path.Base(filepath.ToSlash(dep.URL))
+			override.Platforms[platform] = append(override.Platforms[platform], dependencyEntry{
+				Name:       dep.Name,
+				Version:    dep.Version,
+				URL:        name,
+				SHA256:     dep.SHA256,
+				Format:     dep.Format,
+				Binary:     dep.Binary,
+				Executable: dep.IsExecutable,
+			})
+			if fetched[name] {
+				continue
+			}
+
+			fmt.Printf("==== %s %s (%s) ====\n", dep.Name, dep.Version, platform)
+			dest := filepath.Join(dir, name)
+			if digest, err := FileSHA256(dest); err == nil && strings.EqualFold(digest, dep.SHA256) {
+				fmt.Printf("이미 받은 파일 사용: %s\n\n", dest)
+				fetched[name] = true
+				continue
+			}
+			digest, err := fetchFile(ctx, dep.URL, dest)
+			if err != nil {
+				return fmt.Errorf("%s 받기 실패: %v", dep.Name, err)
+			}
+			if err := verifyDigest(dep, digest); err != nil {
+				os.Remove(dest)
+				return err
+			}
+			fetched[name] = true
+		}
+	}
+
+	data, err := json.MarshalIndent(override, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, config.DependencyManifestFile), data, 0644)
+}
+
+// Platforms 의존성 목록이 있는 플랫폼 (정렬)
+func Platforms() []string {
+	var platforms []string
+	for platform := range manifests {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
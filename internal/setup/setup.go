@@ -5,8 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,71 +17,17 @@ import (
 // 다운로드할 의존성 정보 구조체
 type Dependency struct {
 	Name         string
+	Version      string // 고정한 버전 (URL도 이 버전의 파일을 가리켜야 함)
 	URL          string
+	SHA256       string // 받은 파일의 SHA-256 (16진수), 일치하지 않으면 설치하지 않음
 	DesiredName  string
-	IsExecutable bool
-}
-
-// 다운로드 의존성 목록
-var dependencies = []Dependency{
-	{
-		Name:         "ffmpeg",
-		URL:          "https://github.com/BtbN/FFmpeg-Builds/releases/download/latest/ffmpeg-master-latest-win64-gpl.zip",
-		DesiredName:  "ffmpeg",
-		IsExecutable: false,
-	},
-	{
-		Name:         "streamlink",
-		URL:          "https://github.com/streamlink/windows-builds/releases/download/7.1.2-2/streamlink-7.1.2-2-py312-x86_64.zip",
-		DesiredName:  "streamlink",
-		IsExecutable: false,
-	},
-}
-
-// DownloadFile URL에서 파일을 다운로드하는 함수
-func DownloadFile(url, destPath string) error {
-	// HTTP 요청 준비
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return err
-	}
-
-	// 다운로드 시작
-	fmt.Printf("다운로드 시작: %s\n", url)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("다운로드 실패: HTTP %d", resp.StatusCode)
-	}
-
-	// 파일 생성
-	os.MkdirAll(filepath.Dir(destPath), 0755)
-	out, err := os.Create(destPath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	// 파일 쓰기
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("다운로드 완료: %s\n\n", destPath)
-	return nil
+	IsExecutable bool   // 압축 파일이 아닌 실행 파일 하나 (예: AppImage)
+	Format       string // 압축 형식 (zip, tar.xz)
+	Binary       string // 압축을 푼 폴더 안의 실행 파일 경로 (bin/<이름>이 아니면 설치 후 옮김)
 }
 
 // ExtractZip 압축 파일을 지정된 경로에 해제하는 함수
+// 경로 밖을 가리키는 항목, 안전하지 않은 링크, 크기 상한 초과는 오류로 처리
 func ExtractZip(zipPath, extractDir string) (string, error) {
 	// 압축 파일 열기
 	reader, err := zip.OpenReader(zipPath)
@@ -92,59 +38,105 @@ func ExtractZip(zipPath, extractDir string) (string, error) {
 
 	// 임시 추출 디렉토리 생성
 	tempExtractDir := extractDir + "_temp"
-	os.RemoveAll(tempExtractDir)
-	os.MkdirAll(tempExtractDir, 0755)
+	x, err := newExtractor(tempExtractDir)
+	if err != nil {
+		return "", err
+	}
 
 	fmt.Printf("압축 해제 중: %s -> %s\n", zipPath, tempExtractDir)
 
-	// 최상위 디렉토리 추출
-	var topDirs []string
+	// 압축 해제
 	for _, file := range reader.File {
-		if strings.Contains(file.Name, "/") {
-			dir := strings.Split(file.Name, "/")[0]
-			if dir != "" && !contains(topDirs, dir) {
-				topDirs = append(topDirs, dir)
-			}
+		if err := extractZipEntry(x, file); err != nil {
+			return "", err
 		}
 	}
 
-	// 압축 해제
-	for _, file := range reader.File {
-		path := filepath.Join(tempExtractDir, file.Name)
-
-		// 디렉토리 생성
-		if file.FileInfo().IsDir() {
-			os.MkdirAll(path, file.Mode())
-			continue
-		}
+	// 최상위 디렉토리 반환
+	if len(x.topDirs) > 0 {
+		return filepath.Join(tempExtractDir, x.topDirs[0]), nil
+	}
 
-		// 파일 생성 전 디렉토리 확인
-		os.MkdirAll(filepath.Dir(path), 0755)
+	return tempExtractDir, nil
+}
 
-		// 파일 생성
-		outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+// extractZipEntry zip 항목 하나를 해제
+func extractZipEntry(x *extractor, file *zip.File) error {
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return x.dir(file.Name)
+	case mode&os.ModeSymlink != 0:
+		// zip의 심볼릭 링크는 내용이 링크 대상
+		inFile, err := file.Open()
 		if err != nil {
-			return "", err
+			return err
 		}
-
-		// 파일 내용 복사
-		inFile, err := file.Open()
+		defer inFile.Close()
+		linkTarget, err := io.ReadAll(io.LimitReader(inFile, 4096))
 		if err != nil {
-			outFile.Close()
-			return "", err
+			return err
 		}
-
-		_, err = io.Copy(outFile, inFile)
-		outFile.Close()
-		inFile.Close()
+		return x.symlink(file.Name, string(linkTarget))
+	case mode.IsRegular():
+		inFile, err := file.Open()
 		if err != nil {
-			return "", err
+			return err
 		}
+		defer inFile.Close()
+		return x.file(file.Name, mode, inFile)
+	default:
+		return &UnsafeEntryError{Name: file.Name, Reason: "지원하지 않는 항목 종류입니다"}
+	}
+}
+
+// ExtractTarXz tar.xz 파일을 지정된 경로에 해제하는 함수
+// Go 표준 라이브러리에 xz 해제가 없으므로 xz 명령어로 푼 tar 스트림을 읽음
+// 경로 밖을 가리키는 항목, 안전하지 않은 링크, 크기 상한 초과는 오류로 처리
+func ExtractTarXz(archivePath, extractDir string) (string, error) {
+	xzPath, err := exec.LookPath("xz")
+	if err != nil {
+		return "", fmt.Errorf("tar.xz 압축 해제에 필요한 xz 명령어가 없습니다 (xz-utils 패키지를 설치하세요)")
+	}
+
+	// 임시 추출 디렉토리 생성
+	tempExtractDir := extractDir + "_temp"
+	x, err := newExtractor(tempExtractDir)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(xzPath, "-dc", archivePath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("압축 해제 중: %s -> %s\n", archivePath, tempExtractDir)
+
+	extractErr := extractTar(x, stdout)
+
+	// 실패했으면 xz를 멈추고, 아니면 남은 출력을 비워 xz가 끝날 수 있게 함
+	if extractErr != nil {
+		cmd.Process.Kill()
+	}
+	io.Copy(io.Discard, stdout)
+	waitErr := cmd.Wait()
+	if extractErr != nil {
+		return "", extractErr
+	}
+	if waitErr != nil {
+		return "", fmt.Errorf("xz 압축 해제 실패: %v %s", waitErr, strings.TrimSpace(stderr.String()))
 	}
 
 	// 최상위 디렉토리 반환
-	if len(topDirs) > 0 {
-		return filepath.Join(tempExtractDir, topDirs[0]), nil
+	if len(x.topDirs) == 1 {
+		return filepath.Join(tempExtractDir, x.topDirs[0]), nil
 	}
 
 	return tempExtractDir, nil
@@ -163,10 +155,14 @@ func contains(arr []string, val string) bool {
 // MoveDir 디렉토리를 이동하는 함수
 func MoveDir(src, dst string) error {
 	// 대상 디렉토리 제거
-	os.RemoveAll(dst)
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
 
 	// 부모 디렉토리 생성
-	os.MkdirAll(filepath.Dir(dst), 0755)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
 
 	// 이동
 	return os.Rename(src, dst)
@@ -187,33 +183,45 @@ func EnsureDirectory(path string) error {
 }
 
 // CheckDependencies 의존성이 설치되어 있는지 확인하는 함수
+// 설치한 파일이 없어도 PATH에 있으면 설치된 것으로 봄
 func CheckDependencies() bool {
-	dependentDir := config.GetDependentDir()
-
-	// 의존성 디렉토리 확인
-	if _, err := os.Stat(dependentDir); os.IsNotExist(err) {
+	deps, err := Dependencies()
+	if err != nil {
+		fmt.Println(err)
 		return false
 	}
-
-	// 필요한 의존성 파일 경로들
-	paths := []string{
-		config.GetFFmpeg(),
-		config.GetStreamlink(),
+	if deps == nil {
+		// 자동 설치를 지원하지 않는 플랫폼은 PATH의 ffmpeg만 확인
+		_, err := os.Stat(config.GetFFmpeg())
+		return err == nil
 	}
 
-	// 모든 의존성 파일 존재 여부 확인
-	for _, path := range paths {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+	for _, dep := range deps {
+		if !dep.Installed() {
 			return false
 		}
 	}
-
 	return true
 }
 
+// Installed 의존성 실행 파일이 있는지 여부 (설치한 파일 또는 PATH)
+func (d Dependency) Installed() bool {
+	_, err := os.Stat(d.Path())
+	return err == nil
+}
+
 // InstallDependencies 의존성을 설치하는 함수
-func InstallDependencies() error {
-	baseDir := config.GetBaseDir()
+// PATH에 이미 있는 의존성은 설치하지 않으며, ctx가 취소되면 받던 파일을 남기고 중단
+func InstallDependencies(ctx context.Context) error {
+	deps, err := Dependencies()
+	if err != nil {
+		return err
+	}
+	if deps == nil {
+		return fmt.Errorf("이 플랫폼(%s)은 자동 설치를 지원하지 않습니다. ffmpeg를 설치해 PATH에 추가하세요", Platform())
+	}
+
+	baseDir := config.GetDataDir()
 	dependentDir := config.GetDependentDir()
 
 	// 기본 디렉토리 확인
@@ -222,84 +230,151 @@ func InstallDependencies() error {
 	}
 
 	// 각 의존성 다운로드 및 설치
-	for _, dep := range dependencies {
+	var failed []string
+	for _, dep := range deps {
+		if err := recoverInterrupted(dep); err != nil {
+			fmt.Printf("%s 이전 설치 정리 실패: %v\n", dep.Name, err)
+		}
+		if dep.Installed() {
+			fmt.Printf("%s 사용: %s\n\n", dep.Name, dep.Path())
+			continue
+		}
+
 		fmt.Printf("==== %s 설치 시작 ====\n", dep.Name)
+		if err := installDependency(ctx, dep, baseDir, dependentDir); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			fmt.Printf("%s 설치 중 오류 발생: %v\n\n", dep.Name, err)
+			failed = append(failed, dep.Name)
+			continue
+		}
+		fmt.Printf("==== %s 설치 완료 ====\n\n", dep.Name)
+	}
 
-		// 실행 파일인 경우
-		if dep.IsExecutable {
-			tmpExePath := filepath.Join(baseDir, dep.Name+".exe")
+	if len(failed) > 0 {
+		return fmt.Errorf("설치하지 못한 의존성: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
 
-			// 다운로드
-			if err := DownloadFile(dep.URL, tmpExePath); err != nil {
-				fmt.Printf("%s 다운로드 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+// installDependency 의존성 하나를 받아 dependent/<이름>/bin/<이름>에 실행 파일이 오도록 설치
+// 받은 파일의 SHA-256이 목록의 값과 같을 때만 압축을 풀거나 실행 파일로 설치함
+// 설치는 dependent/<이름>.new에 준비한 뒤 한 번에 바꾸므로 실패해도 기존 설치가 남음
+func installDependency(ctx context.Context, dep Dependency, baseDir, dependentDir string) error {
+	if err := checkPinned(dep); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", dep.Name, dep.Version)
 
-			// 디렉토리 생성
-			finalDir := filepath.Join(dependentDir, dep.DesiredName)
-			if err := EnsureDirectory(finalDir); err != nil {
-				os.Remove(tmpExePath)
-				fmt.Printf("%s 디렉토리 생성 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+	stageDir := filepath.Join(dependentDir, dep.DesiredName+".new")
+	if err := os.RemoveAll(stageDir); err != nil {
+		return err
+	}
+	defer os.RemoveAll(stageDir)
 
-			// 파일 이동
-			finalExePath := filepath.Join(finalDir, filepath.Base(tmpExePath))
-			if _, err := os.Stat(finalExePath); err == nil {
-				os.Remove(finalExePath)
-			}
+	if err := stageDependency(ctx, dep, baseDir, stageDir); err != nil {
+		return err
+	}
+	return swapInstall(dep, stageDir)
+}
 
-			if err := os.Rename(tmpExePath, finalExePath); err != nil {
-				os.Remove(tmpExePath)
-				fmt.Printf("%s 파일 이동 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+// stageDependency 의존성을 받아 stageDir/bin/<이름>에 실행 파일이 오도록 준비하고 설치 기록을 남김
+func stageDependency(ctx context.Context, dep Dependency, baseDir, stageDir string) error {
+	stageExePath := filepath.Join(stageDir, "bin", config.ExecutableName(dep.DesiredName))
+	var digest string
 
-			fmt.Printf("%s 파일 이동 완료: %s\n", dep.Name, finalExePath)
+	if dep.IsExecutable {
+		// 실행 파일인 경우
+		// 이름에 버전을 넣어 다른 버전의 받다 만 파일(.part)을 이어받지 않게 함
+		tmpExePath := filepath.Join(baseDir, config.ExecutableName(dep.Name+"-"+dep.Version)+".download")
+		defer os.Remove(tmpExePath)
 
-		} else {
-			// ZIP 파일인 경우
-			tmpZipPath := filepath.Join(baseDir, dep.Name+".zip")
+		// 다운로드 및 검증
+		var err error
+		digest, err = fetchFile(ctx, dep.URL, tmpExePath)
+		if err != nil {
+			return err
+		}
+		if err := verifyDigest(dep, digest); err != nil {
+			return err
+		}
 
-			// 다운로드
-			if err := DownloadFile(dep.URL, tmpZipPath); err != nil {
-				fmt.Printf("%s 다운로드 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+		// 파일 이동
+		if err := os.MkdirAll(filepath.Dir(stageExePath), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpExePath, stageExePath); err != nil {
+			return err
+		}
+	} else {
+		// 압축 파일인 경우
+		tmpArchivePath := filepath.Join(baseDir, dep.Name+"-"+dep.Version+"."+dep.Format)
+		defer os.Remove(tmpArchivePath)
 
-			// 압축 해제
-			extractedPath, err := ExtractZip(tmpZipPath, filepath.Join(baseDir, "temp_extract_"+dep.Name))
-			if err != nil {
-				os.Remove(tmpZipPath)
-				fmt.Printf("%s 압축 해제 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+		// 다운로드 및 검증 (압축을 풀기 전에 확인)
+		var err error
+		digest, err = fetchFile(ctx, dep.URL, tmpArchivePath)
+		if err != nil {
+			return err
+		}
+		if err := verifyDigest(dep, digest); err != nil {
+			return err
+		}
 
-			// 디렉토리 이동
-			finalPath := filepath.Join(dependentDir, dep.DesiredName)
-			if err := MoveDir(extractedPath, finalPath); err != nil {
-				os.Remove(tmpZipPath)
-				os.RemoveAll(filepath.Dir(extractedPath))
-				fmt.Printf("%s 디렉토리 이동 중 오류 발생: %v\n", dep.Name, err)
-				continue
-			}
+		// 압축 해제
+		extractDir := filepath.Join(baseDir, "temp_extract_"+dep.Name)
+		defer os.RemoveAll(extractDir + "_temp")
 
-			// 임시 파일 정리
-			os.Remove(tmpZipPath)
-			os.RemoveAll(filepath.Dir(extractedPath))
+		var extractedPath string
+		switch dep.Format {
+		case FormatTarXz:
+			extractedPath, err = ExtractTarXz(tmpArchivePath, extractDir)
+		default:
+			extractedPath, err = ExtractZip(tmpArchivePath, extractDir)
+		}
+		if err != nil {
+			return fmt.Errorf("압축 해제 실패: %v", err)
+		}
 
-			fmt.Printf("%s 설치 완료: %s\n", dep.Name, finalPath)
+		// 디렉토리 이동
+		if err := MoveDir(extractedPath, stageDir); err != nil {
+			return fmt.Errorf("디렉토리 이동 실패: %v", err)
 		}
 
-		fmt.Printf("==== %s 설치 완료 ====\n\n", dep.Name)
+		// 실행 파일이 bin/<이름>에 있지 않으면 옮김 (예: macOS ffmpeg zip)
+		binary := filepath.Join(stageDir, filepath.FromSlash(dep.Binary))
+		if binary != stageExePath {
+			if err := os.MkdirAll(filepath.Dir(stageExePath), 0755); err != nil {
+				return err
+			}
+			if err := os.Rename(binary, stageExePath); err != nil {
+				return fmt.Errorf("실행 파일 이동 실패: %v", err)
+			}
+		}
 	}
 
-	// streamlink의 중복 ffmpeg 제거
-	streamlinkFFmpeg := filepath.Join(dependentDir, "streamlink", "ffmpeg")
-	if _, err := os.Stat(streamlinkFFmpeg); err == nil {
-		os.RemoveAll(streamlinkFFmpeg)
-		fmt.Printf("중복된 ffmpeg 폴더 삭제 완료: %s\n", streamlinkFFmpeg)
+	if err := os.Chmod(stageExePath, 0755); err != nil {
+		return err
 	}
 
-	return nil
+	// streamlink 배포판에 들어 있는 중복 ffmpeg 제거
+	if dep.Name == "streamlink" {
+		if err := os.RemoveAll(filepath.Join(stageDir, "ffmpeg")); err != nil {
+			return err
+		}
+	}
+
+	binaryDigest, err := FileSHA256(stageExePath)
+	if err != nil {
+		return err
+	}
+	return writeInstallRecord(stageDir, InstallRecord{
+		Name:         dep.Name,
+		Version:      dep.Version,
+		URL:          dep.URL,
+		SHA256:       digest,
+		BinarySHA256: binaryDigest,
+		InstalledAt:  time.Now(),
+	})
 }
@@ -0,0 +1,77 @@
+package setup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"chzzk-downloader/internal/config"
+)
+
+// ChecksumError 받은 파일의 SHA-256이 목록에 등록된 값과 다를 때의 오류
+type ChecksumError struct {
+	Name     string
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s 파일의 SHA-256이 일치하지 않아 설치하지 않았습니다 (예상: %s, 실제: %s, 주소: %s). "+
+		"다운로드 서버나 미러의 파일이 바뀌었거나 변조되었을 수 있습니다",
+		e.Name, e.Expected, e.Actual, e.URL)
+}
+
+// UnpinnedError 목록에 SHA-256이 등록되지 않은 의존성을 설치하려 할 때의 오류
+type UnpinnedError struct {
+	Name    string
+	Version string
+}
+
+func (e *UnpinnedError) Error() string {
+	return fmt.Sprintf("%s %s는 SHA-256이 등록되지 않아 자동으로 설치하지 않습니다. "+
+		"직접 설치해 PATH에 추가하거나, 배포 페이지에서 확인한 SHA-256을 %s의 sha256 항목에 등록하세요", e.Name, e.Version, config.DependencyManifestFile)
+}
+
+// checkPinned 의존성에 버전과 SHA-256이 등록되어 있는지 확인
+func checkPinned(dep Dependency) error {
+	if !isSHA256(dep.SHA256) {
+		return &UnpinnedError{Name: dep.Name, Version: dep.Version}
+	}
+	return nil
+}
+
+// verifyDigest 받은 파일의 SHA-256이 등록된 값과 같은지 확인
+func verifyDigest(dep Dependency, actual string) error {
+	if !strings.EqualFold(dep.SHA256, actual) {
+		return &ChecksumError{Name: dep.Name, URL: dep.URL, Expected: strings.ToLower(dep.SHA256), Actual: actual}
+	}
+	return nil
+}
+
+// FileSHA256 파일의 SHA-256 (16진수)
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isSHA256 64자리 16진수인지 확인
+func isSHA256(digest string) bool {
+	if len(digest) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(digest)
+	return err == nil
+}
@@ -0,0 +1,262 @@
+package update
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"chzzk-downloader/internal/setup"
+)
+
+// DefaultAPIURL 최신 릴리스를 알려주는 GitHub API 주소
+const DefaultAPIURL = "https://api.github.com/repos/chnu-kim/chzzk-downloader/releases/latest"
+
+// 릴리스에 함께 올리는 SHA-256 목록 파일 (sha256sum 형식: "<16진수>  <파일명>")
+const checksumsAsset = "checksums.txt"
+
+// 릴리스 정보, 체크섬 목록 요청 제한 시간
+const apiTimeout = 30 * time.Second
+
+// Asset 릴리스에 올린 파일
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"browser_download_url"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"` // "sha256:<16진수>" (GitHub가 계산한 값, 없을 수 있음)
+}
+
+// Release GitHub 릴리스 정보
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Name       string  `json:"name"`
+	HTMLURL    string  `json:"html_url"`
+	Body       string  `json:"body"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Version 태그에서 읽은 릴리스 버전
+func (r *Release) Version() (Version, error) {
+	return ParseVersion(r.TagName)
+}
+
+// Asset 이름으로 릴리스 파일 찾기
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// AssetName 운영체제/아키텍처별 실행 파일 이름 (예: chzzk-downloader_linux_amd64)
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("chzzk-downloader_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Updater 릴리스 확인과 실행 파일 교체
+// APIURL을 바꾸면 GitHub 대신 같은 형식의 다른 서버(테스트용 로컬 서버 등)를 사용
+type Updater struct {
+	APIURL  string
+	Current string // 현재 버전
+	Client  *http.Client
+}
+
+func (u *Updater) client() *http.Client {
+	if u.Client != nil {
+		return u.Client
+	}
+	return http.DefaultClient
+}
+
+// get 짧은 응답(릴리스 정보, 체크섬 목록)을 요청
+func (u *Updater) get(ctx context.Context, url, accept string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "chzzk-downloader/"+u.Current)
+
+	resp, err := u.client().Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// Latest 최신 릴리스 정보 요청
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	resp, err := u.get(ctx, u.APIURL, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("릴리스 정보 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("공개된 릴리스가 없습니다")
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return nil, fmt.Errorf("GitHub API 요청 한도를 넘었습니다. 잠시 후 다시 시도하세요")
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("릴리스 정보 요청 실패: HTTP %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("릴리스 정보를 읽을 수 없습니다: %v", err)
+	}
+	return &release, nil
+}
+
+// Check 최신 릴리스를 받아 현재 버전보다 높은지 확인
+func (u *Updater) Check(ctx context.Context) (*Release, bool, error) {
+	current, err := ParseVersion(u.Current)
+	if err != nil {
+		return nil, false, err
+	}
+	release, err := u.Latest(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	latest, err := release.Version()
+	if err != nil {
+		return nil, false, fmt.Errorf("릴리스 태그 %q: %v", release.TagName, err)
+	}
+	return release, latest.Compare(current) > 0, nil
+}
+
+// expectedDigest 릴리스 파일의 SHA-256
+// GitHub가 계산한 digest가 있으면 사용하고, 없으면 릴리스의 checksums.txt에서 찾음
+func (u *Updater) expectedDigest(ctx context.Context, release *Release, asset Asset) (string, error) {
+	if digest, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+		return strings.ToLower(digest), nil
+	}
+
+	checksums, ok := release.Asset(checksumsAsset)
+	if !ok {
+		return "", fmt.Errorf("릴리스에 %s 파일의 SHA-256이 없어 설치하지 않습니다", asset.Name)
+	}
+	resp, err := u.get(ctx, checksums.URL, "application/octet-stream")
+	if err != nil {
+		return "", fmt.Errorf("%s 요청 실패: %v", checksumsAsset, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s 요청 실패: HTTP %s", checksumsAsset, resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset.Name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s에 %s 항목이 없어 설치하지 않습니다", checksumsAsset, asset.Name)
+}
+
+// Apply 릴리스에서 현재 플랫폼의 실행 파일을 받아 SHA-256을 확인한 뒤 exePath와 바꿈
+func (u *Updater) Apply(ctx context.Context, release *Release, exePath string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	asset, ok := release.Asset(name)
+	if !ok {
+		return fmt.Errorf("%s 릴리스에 이 플랫폼(%s/%s)용 파일(%s)이 없습니다", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	expected, err := u.expectedDigest(ctx, release, asset)
+	if err != nil {
+		return err
+	}
+
+	// 같은 폴더에 받아야 이름 바꾸기로 교체할 수 있음
+	newPath := exePath + ".new"
+	digest, err := setup.DownloadFile(ctx, asset.URL, newPath)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(digest, expected) {
+		os.Remove(newPath)
+		return fmt.Errorf("%s의 SHA-256이 일치하지 않아 설치하지 않았습니다 (예상: %s, 실제: %s)", asset.Name, expected, digest)
+	}
+	if err := os.Chmod(newPath, 0755); err != nil {
+		os.Remove(newPath)
+		return err
+	}
+
+	if err := replaceExecutable(exePath, newPath); err != nil {
+		os.Remove(newPath)
+		return err
+	}
+	return nil
+}
+
+// replaceExecutable newPath를 exePath로 옮겨 실행 파일 교체
+// Windows는 실행 중인 exe를 덮어쓰거나 지울 수 없지만 이름은 바꿀 수 있으므로
+// 먼저 <exe>.old로 옮긴 뒤 새 파일을 놓고, .old는 다음 실행 때 CleanupOld로 지움
+func replaceExecutable(exePath, newPath string) error {
+	if runtime.GOOS != "windows" {
+		// 실행 중인 프로세스는 기존 파일을 계속 사용하므로 바로 바꿔도 됨
+		return os.Rename(newPath, exePath)
+	}
+
+	oldPath := exePath + ".old"
+	if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("이전 업데이트 파일을 지울 수 없습니다: %v", err)
+	}
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("실행 파일 이름 변경 실패: %v", err)
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		if restoreErr := os.Rename(oldPath, exePath); restoreErr != nil {
+			return fmt.Errorf("새 실행 파일 설치 실패: %v (복구 실패: %v, %s을(를) 직접 되돌리세요)", err, restoreErr, oldPath)
+		}
+		return fmt.Errorf("새 실행 파일 설치 실패: %v", err)
+	}
+	return nil
+}
+
+// CleanupOld 지난 업데이트에서 남은 <exe>.old 삭제 (실행 중이라 지우지 못한 파일)
+func CleanupOld(exePath string) {
+	os.Remove(exePath + ".old")
+}
+
+// ExecutablePath 현재 실행 파일의 실제 경로 (심볼릭 링크를 따라감)
+func ExecutablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exePath)
+}
+
+// cancelOnClose 응답 본문을 닫을 때 요청 context도 정리
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,359 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// releaseServer 릴리스 정보와 파일을 돌려주는 로컬 릴리스 서버
+// files의 키는 경로(/download/<이름>), 릴리스의 asset URL은 서버 주소로 채움
+type releaseServer struct {
+	*httptest.Server
+	release Release
+	files   map[string][]byte
+}
+
+func newReleaseServer(t *testing.T, tag string) *releaseServer {
+	t.Helper()
+	s := &releaseServer{
+		release: Release{TagName: tag, HTMLURL: "https://example.invalid/" + tag},
+		files:   make(map[string][]byte),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(s.release)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := s.files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, filepath.Base(r.URL.Path), time.Time{}, strings.NewReader(string(data)))
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addAsset 릴리스에 파일 추가 (digest가 비어 있으면 GitHub digest 없이 올린 것처럼)
+func (s *releaseServer) addAsset(name string, data []byte, digest string) {
+	path := "/download/" + name
+	s.files[path] = data
+	s.release.Assets = append(s.release.Assets, Asset{
+		Name:   name,
+		URL:    s.URL + path,
+		Size:   int64(len(data)),
+		Digest: digest,
+	})
+}
+
+func (s *releaseServer) updater(current string) *Updater {
+	return &Updater{APIURL: s.URL + "/releases/latest", Current: current, Client: s.Client()}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"0.3.0", Version{Major: 0, Minor: 3, Patch: 0}},
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{" v1.2.3-beta.1 ", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.1"}},
+		{"1.2.3+build.5", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"1.2.3-rc.1+build", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1.2", "1.2.3.4", "v1.x.3", "1.-2.3", "latest"} {
+		if _, err := ParseVersion(in); err == nil {
+			t.Errorf("ParseVersion(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// 낮은 버전부터 차례로 (semver.org의 우선순위 예시 포함)
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+		"10.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := ParseVersion(ordered[i])
+			b, _ := ParseVersion(ordered[j])
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got := a.Compare(b); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		tag       string
+		wantNewer bool
+	}{
+		{"newer", "0.3.0", "v0.4.0", true},
+		{"same", "0.3.0", "v0.3.0", false},
+		{"older", "0.4.0", "v0.3.9", false},
+		{"release after prerelease", "0.4.0-beta.1", "v0.4.0", true},
+		{"prerelease of current", "0.4.0", "v0.4.0-rc.1", false},
+		{"newer prerelease", "0.4.0-beta.1", "v0.4.0-beta.2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newReleaseServer(t, tt.tag)
+			release, newer, err := server.updater(tt.current).Check(context.Background())
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if release.TagName != tt.tag {
+				t.Errorf("TagName = %q, want %q", release.TagName, tt.tag)
+			}
+			if newer != tt.wantNewer {
+				t.Errorf("newer = %v, want %v", newer, tt.wantNewer)
+			}
+		})
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	server := newReleaseServer(t, "nightly")
+	if _, _, err := server.updater("0.3.0").Check(context.Background()); err == nil {
+		t.Error("Check with an invalid tag succeeded")
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	updater := &Updater{APIURL: missing.URL, Current: "0.3.0", Client: missing.Client()}
+	if _, _, err := updater.Check(context.Background()); err == nil {
+		t.Error("Check without a release succeeded")
+	}
+}
+
+func TestExpectedDigest(t *testing.T) {
+	binary := []byte("new binary")
+	digest := sha256Hex(binary)
+	name := AssetName("linux", "amd64")
+
+	t.Run("digest field", func(t *testing.T) {
+		server := newReleaseServer(t, "v1.0.0")
+		server.addAsset(name, binary, "sha256:"+strings.ToUpper(digest))
+		// 필드가 있으면 checksums.txt보다 우선
+		server.addAsset(checksumsAsset, []byte(strings.Repeat("0", 64)+"  "+name+"\n"), "")
+
+		asset, _ := server.release.Asset(name)
+		got, err := server.updater("0.1.0").expectedDigest(context.Background(), &server.release, asset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != digest {
+			t.Errorf("digest = %s, want %s", got, digest)
+		}
+	})
+
+	t.Run("checksums.txt", func(t *testing.T) {
+		server := newReleaseServer(t, "v1.0.0")
+		server.addAsset(name, binary, "")
+		checksums := fmt.Sprintf("%s  other_file\n%s *%s\n", strings.Repeat("1", 64), strings.ToUpper(digest), name)
+		server.addAsset(checksumsAsset, []byte(checksums), "")
+
+		asset, _ := server.release.Asset(name)
+		got, err := server.updater("0.1.0").expectedDigest(context.Background(), &server.release, asset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != digest {
+			t.Errorf("digest = %s, want %s", got, digest)
+		}
+	})
+
+	t.Run("not listed", func(t *testing.T) {
+		server := newReleaseServer(t, "v1.0.0")
+		server.addAsset(name, binary, "")
+		server.addAsset(checksumsAsset, []byte(strings.Repeat("1", 64)+"  other_file\n"), "")
+
+		asset, _ := server.release.Asset(name)
+		if _, err := server.updater("0.1.0").expectedDigest(context.Background(), &server.release, asset); err == nil {
+			t.Error("expectedDigest succeeded without a checksum for the asset")
+		}
+	})
+
+	t.Run("no checksum", func(t *testing.T) {
+		server := newReleaseServer(t, "v1.0.0")
+		server.addAsset(name, binary, "")
+
+		asset, _ := server.release.Asset(name)
+		if _, err := server.updater("0.1.0").expectedDigest(context.Background(), &server.release, asset); err == nil {
+			t.Error("expectedDigest succeeded without any checksum")
+		}
+	})
+}
+
+// writeExecutable 교체 대상 실행 파일 준비
+func writeExecutable(t *testing.T, data []byte) string {
+	t.Helper()
+	exePath := filepath.Join(t.TempDir(), AssetName(runtime.GOOS, runtime.GOARCH))
+	if err := os.WriteFile(exePath, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return exePath
+}
+
+func assertFileContent(t *testing.T, path string, want []byte) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), got, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	oldBinary := []byte("old binary")
+	newBinary := []byte("new binary")
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+
+	server := newReleaseServer(t, "v1.0.0")
+	server.addAsset(name, newBinary, "sha256:"+sha256Hex(newBinary))
+	exePath := writeExecutable(t, oldBinary)
+
+	if err := server.updater("0.1.0").Apply(context.Background(), &server.release, exePath); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	assertFileContent(t, exePath, newBinary)
+	if _, err := os.Stat(exePath + ".new"); !os.IsNotExist(err) {
+		t.Errorf("%s.new was left behind", filepath.Base(exePath))
+	}
+}
+
+func TestApplyDigestMismatch(t *testing.T) {
+	oldBinary := []byte("old binary")
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+
+	server := newReleaseServer(t, "v1.0.0")
+	server.addAsset(name, []byte("tampered binary"), "sha256:"+sha256Hex([]byte("new binary")))
+	exePath := writeExecutable(t, oldBinary)
+
+	err := server.updater("0.1.0").Apply(context.Background(), &server.release, exePath)
+	if err == nil {
+		t.Fatal("Apply succeeded with a mismatching digest")
+	}
+	if !strings.Contains(err.Error(), "SHA-256") {
+		t.Errorf("error does not mention the checksum: %v", err)
+	}
+	assertFileContent(t, exePath, oldBinary)
+
+	entries, err := os.ReadDir(filepath.Dir(exePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(exePath) {
+			t.Errorf("unexpected file left next to the executable: %s", entry.Name())
+		}
+	}
+}
+
+func TestApplyMissingAsset(t *testing.T) {
+	oldBinary := []byte("old binary")
+	server := newReleaseServer(t, "v1.0.0")
+	server.addAsset(AssetName("plan9", "mips"), []byte("other"), "")
+	exePath := writeExecutable(t, oldBinary)
+
+	if err := server.updater("0.1.0").Apply(context.Background(), &server.release, exePath); err == nil {
+		t.Fatal("Apply succeeded without an asset for this platform")
+	}
+	assertFileContent(t, exePath, oldBinary)
+}
+
+func TestApplyCanceled(t *testing.T) {
+	oldBinary := []byte("old binary")
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+
+	// 일부만 보내고 요청이 취소될 때까지 멈춰 있는 서버
+	sent := make(chan struct{})
+	server := newReleaseServer(t, "v1.0.0")
+	server.release.Assets = append(server.release.Assets, Asset{
+		Name:   name,
+		URL:    server.URL + "/stall/" + name,
+		Digest: "sha256:" + strings.Repeat("0", 64),
+	})
+	server.Config.Handler.(*http.ServeMux).HandleFunc("/stall/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		close(sent)
+		<-r.Context().Done()
+	})
+	exePath := writeExecutable(t, oldBinary)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-sent
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.updater("0.1.0").Apply(ctx, &server.release, exePath)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Apply error = %v, want context.Canceled", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Apply ignored the canceled context")
+	}
+	assertFileContent(t, exePath, oldBinary)
+}
@@ -81,9 +81,12 @@ func ValidateTimeRange(timeRange string) (bool, error) {
 	return matched, err
 }
 
-// HmsToSeconds 시:분:초 형식을 초 단위로 변환하는 함수
+// HmsToSeconds 시:분:초 형식을 초 단위로 변환하는 함수 (분:초 형식도 허용)
 func HmsToSeconds(hms string) int {
-	parts := strings.Split(hms, ":")
+	parts := strings.Split(strings.TrimSpace(hms), ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
 	if len(parts) != 3 {
 		return 0
 	}
@@ -112,3 +115,60 @@ func SecondsToHms(seconds int) string {
 	s := seconds % 60
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
 }
+
+// FormatBytes 바이트 크기를 사람이 읽기 쉬운 형식으로 변환하는 함수 (1024 단위, 예: 1.5 GB)
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseByteSize "2GiB", "500MB", "700M" 같은 크기 문자열을 바이트 단위로 변환하는 함수
+// 단위가 없으면 바이트, KB/MB/GB는 1000 단위, K/M/G와 KiB/MiB/GiB는 1024 단위로 계산
+func ParseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+
+	numberPart, unitPart := s, ""
+	if idx >= 0 {
+		numberPart, unitPart = s[:idx], strings.TrimSpace(s[idx:])
+	}
+
+	value, err := strconv.ParseFloat(numberPart, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("올바르지 않은 크기: %s", s)
+	}
+
+	multipliers := map[string]float64{
+		"":    1,
+		"B":   1,
+		"K":   1 << 10,
+		"KB":  1e3,
+		"KIB": 1 << 10,
+		"M":   1 << 20,
+		"MB":  1e6,
+		"MIB": 1 << 20,
+		"G":   1 << 30,
+		"GB":  1e9,
+		"GIB": 1 << 30,
+		"T":   1 << 40,
+		"TB":  1e12,
+		"TIB": 1 << 40,
+	}
+
+	multiplier, ok := multipliers[strings.ToUpper(unitPart)]
+	if !ok {
+		return 0, fmt.Errorf("알 수 없는 크기 단위: %s", unitPart)
+	}
+
+	return int64(value * multiplier), nil
+}